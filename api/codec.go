@@ -0,0 +1,12 @@
+package api
+
+// Codec converts values to their redis representation and back
+type Codec interface {
+
+	// Encode serializes value to bytes stored in redis
+	Encode(value any) ([]byte, error)
+
+	// Decode deserializes data received from redis into dest
+	//       dest should be a non-nil pointer
+	Decode(data []byte, dest any) error
+}
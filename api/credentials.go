@@ -0,0 +1,7 @@
+package api
+
+import "context"
+
+// CredentialsProvider returns credentials used to authenticate new connection;
+// it is called on every dial, so rotated passwords are picked up by new connections
+type CredentialsProvider func(ctx context.Context) (user, password string, err error)
@@ -0,0 +1,50 @@
+package api
+
+import "time"
+
+// EventType is a kind of client lifecycle event
+type EventType uint8
+
+const (
+	// EventConnected - the first connection to node is established
+	EventConnected EventType = iota
+	// EventDisconnected - node became unreachable, connection could not be re-established
+	EventDisconnected
+	// EventReconnected - connection to previously unreachable node is re-established
+	EventReconnected
+	// EventFailover - sentinel switched master to another node
+	EventFailover
+	// EventTopologyChanged - cluster or sentinel nodes were added, removed or changed
+	EventTopologyChanged
+	// EventPubSubResubscribed - pub/sub connection was re-established and its subscriptions are restored
+	EventPubSubResubscribed
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventConnected:
+		return "connected"
+	case EventDisconnected:
+		return "disconnected"
+	case EventReconnected:
+		return "reconnected"
+	case EventFailover:
+		return "failover"
+	case EventTopologyChanged:
+		return "topology changed"
+	case EventPubSubResubscribed:
+		return "pub/sub resubscribed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes client lifecycle change
+type Event struct {
+	Type EventType
+	// Addr is node address, new master address for failover, empty for cluster topology change
+	Addr string
+	// Err is the cause of disconnection
+	Err  error
+	Time time.Time
+}
@@ -0,0 +1,62 @@
+package api
+
+import "time"
+
+// CircuitState is a state of client-side circuit breaker
+type CircuitState uint8
+
+const (
+	// CircuitClosed - commands are issued as usual
+	CircuitClosed CircuitState = iota
+	// CircuitOpen - commands fail fast without reaching redis
+	CircuitOpen
+	// CircuitHalfOpen - limited amount of probe commands is issued to check whether redis is back
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Health describes client state
+type Health struct {
+	// Circuit is circuit breaker state, always closed if circuit breaker is not configured
+	Circuit CircuitState
+	// Failures is amount of consecutive failed commands counted by circuit breaker
+	Failures int
+	// OpenedAt is the time circuit breaker was opened last time
+	OpenedAt time.Time
+}
+
+// HealthReport is a result of node checks together with client state
+type HealthReport struct {
+	Health
+	// Nodes are checked masters and sentinels
+	Nodes []NodeHealth
+	// Err is nil if all masters are reachable and, for sentinel, quorum is reached
+	Err error
+}
+
+// Ready reports whether client is able to issue commands: all nodes are healthy and circuit is not open
+func (h HealthReport) Ready() bool {
+	return h.Err == nil && h.Circuit != CircuitOpen
+}
+
+// NodeHealth describes single node check
+type NodeHealth struct {
+	Addr string
+	// Role is either "master", "replica" or "sentinel"
+	Role string
+	// Latency is PING round-trip time for redis nodes and SENTINEL CKQUORUM one for sentinels
+	Latency time.Duration
+	Err     error
+}
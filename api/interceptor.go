@@ -0,0 +1,22 @@
+package api
+
+import (
+	"context"
+	"github.com/mediocregopher/radix/v4"
+)
+
+// Command is a redis command passed through interceptor chain
+type Command struct {
+	// Name is upper-cased command name; it is empty for actions which are neither radix commands nor pipelines
+	Name string
+	// Keys are redis keys the command acts on
+	Keys []string
+	// Action performs the command; interceptor may pass another action to the next handler
+	Action radix.Action
+}
+
+// CommandHandler issues command
+type CommandHandler func(ctx context.Context, cmd Command) error
+
+// Interceptor wraps command execution; it should call next to proceed with the command
+type Interceptor func(ctx context.Context, cmd Command, next CommandHandler) error
@@ -7,3 +7,46 @@ type Logger interface {
 	Warning(message string, args ...interface{})
 	Error(message string, args ...interface{})
 }
+
+// StructuredLogger is logger accepting key/value fields; internal messages are passed to loggers implementing it
+// with object key, command and error as fields, other loggers receive them formatted as 'message key=value ...'
+type StructuredLogger interface {
+	Logger
+
+	// Log logs message with fields at given level
+	Log(level LogLevel, message string, fields ...LogField)
+}
+
+// LogLevel is severity of logged message
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelNotice
+	LevelWarning
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelNotice:
+		return "notice"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// LogField is structured log attribute
+type LogField struct {
+	Key   string
+	Value any
+}
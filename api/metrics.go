@@ -0,0 +1,20 @@
+package api
+
+import "time"
+
+// Metrics receives client measurements
+type Metrics interface {
+
+	// CommandDone is called when command reply is received or command fails
+	CommandDone(command string, duration time.Duration, err error)
+
+	// PoolChanged is called when connection of node pool is opened, closed, taken or released;
+	// inUse is amount of connections with commands in flight
+	PoolChanged(addr string, size, open, inUse int)
+
+	// PubSubReconnected is called when persistent pub/sub connection is re-established after failure
+	PubSubReconnected()
+
+	// CacheMapSynced is called when RCacheMap local cache is synchronized with redis
+	CacheMapSynced(key string, duration time.Duration, err error)
+}
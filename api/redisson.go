@@ -1,18 +1,49 @@
 package api
 
 import (
+	"context"
 	"github.com/mediocregopher/radix/v4"
+	"iter"
 	"time"
 )
 
 type Value interface {
+
+	// IsNil reports whether value represents redis nil reply (missing key, field or list item)
+	IsNil() bool
+
 	IsEmpty() bool
 	String() string
 	V() any
 	AsString() string
+	AsBytes() []byte
 	AsInt() int
 	AsFloat() float64
 	AsBool() bool
+
+	// Int64 parses value as an integer number
+	Int64() (int64, error)
+
+	// Uint64 parses value as an unsigned integer number
+	Uint64() (uint64, error)
+
+	// Float64 parses value as a real number
+	Float64() (float64, error)
+
+	// Bool parses value as a boolean
+	Bool() (bool, error)
+
+	// Duration parses value as a duration string (i.e. "1m30s") or amount of nanoseconds
+	Duration() (time.Duration, error)
+
+	// Time parses value as a time of given layout
+	Time(layout string) (time.Time, error)
+
+	// Scan is an alias of Decode
+	Scan(dest any) error
+
+	// Decode decodes value into dest (non-nil pointer) using codec value was read with
+	Decode(dest any) error
 }
 
 type MapEntry struct {
@@ -20,11 +51,32 @@ type MapEntry struct {
 	Value Value
 }
 
+// ScoredEntry is a sorted set member with its score
+type ScoredEntry struct {
+	Value Value
+	Score float64
+}
+
+type RBucket interface {
+
+	// Set stores value in bucket
+	Set(value any) error
+
+	// Get retrieves bucket value; returns ErrNotFound if bucket does not exist
+	Get() (Value, error)
+
+	// WithContext returns view of the bucket which issues commands with given context
+	WithContext(ctx context.Context) RBucket
+}
+
 type RList interface {
 
 	// Len returns length of a list
 	Len() int
 
+	// LenE returns length of a list or an error if it could not be retrieved
+	LenE() (int, error)
+
 	// LPush adds items to list tail in given order
 	LPush(items ...any) error
 
@@ -32,14 +84,17 @@ type RList interface {
 	//       i.e. first item in passed list will be added last
 	LPushRO(items ...any) error
 
-	// LPop get item from list tail
+	// LPop get item from list tail; returns ErrNotFound if list is empty
 	LPop() (Value, error)
 
 	// RPush adds items to list head in given order
 	RPush(items ...any) error
 
-	// RPop get item from list head
+	// RPop get item from list head; returns ErrNotFound if list is empty
 	RPop() (Value, error)
+
+	// WithContext returns view of the list which issues commands with given context
+	WithContext(ctx context.Context) RList
 }
 type RSet interface {
 	Size() int
@@ -47,12 +102,36 @@ type RSet interface {
 	Has(value any) bool
 	Del(keys ...any) error
 	Items() []Value
+	SizeE() (int, error)
+	HasE(value any) (bool, error)
+	ItemsE() ([]Value, error)
+
+	// Scan iterates members matching glob-style pattern with SSCAN; count is a hint of members per call (0 - default)
+	Scan(ctx context.Context, match string, count int) iter.Seq2[Value, error]
+
+	WithContext(ctx context.Context) RSet
+}
+type RSortedSet interface {
+	Size() int
+	SizeE() (int, error)
+
+	// Add adds member with given score or updates score of existing member
+	Add(score float64, value any) error
+
+	// Scan iterates members matching glob-style pattern with their scores with ZSCAN;
+	// count is a hint of members per call (0 - default)
+	Scan(ctx context.Context, match string, count int) iter.Seq2[ScoredEntry, error]
+
+	// WithContext returns view of the sorted set which issues commands with given context
+	WithContext(ctx context.Context) RSortedSet
 }
 type RBitSet interface {
 	Set(idx uint32, value any) (bool, error)
 	Get(idx uint32) (bool, error)
 	BitCount() int
 	BitCountRange(start, end int, unit string) (int, error)
+	BitCountE() (int, error)
+	WithContext(ctx context.Context) RBitSet
 }
 type RMap interface {
 	Set(key string, value any) error
@@ -60,6 +139,16 @@ type RMap interface {
 	Del(keys ...string) error
 	Keys() []string
 	Entries() []MapEntry
+	KeysE() ([]string, error)
+	EntriesE() ([]MapEntry, error)
+
+	// GetE gets value of map key reporting redis errors; false is returned if key does not exist
+	GetE(key string) (Value, bool, error)
+
+	// Scan iterates entries with keys matching glob-style pattern with HSCAN; count is a hint of entries per call (0 - default)
+	Scan(ctx context.Context, match string, count int) iter.Seq2[MapEntry, error]
+
+	WithContext(ctx context.Context) RMap
 }
 type RCacheMap interface {
 	RMap
@@ -75,11 +164,53 @@ type Redis interface {
 
 	Close() error
 
+	// WithContext returns view of the client which issues all commands with given context;
+	//             the view shares connections with the client it was created from
+	WithContext(ctx context.Context) Redis
+
+	// Context returns context commands are issued with
+	Context() context.Context
+
+	// Ping checks that redis is reachable; every master is checked for cluster
+	Ping(ctx context.Context) error
+
+	// Health returns client state, i.e. circuit breaker state; no commands are issued
+	Health() Health
+
+	// CheckHealth checks every master (and sentinel quorum for sentinel) and reports
+	//             their roles and latencies together with circuit breaker state
+	CheckHealth(ctx context.Context) HealthReport
+
+	// Namespace returns view of the client prefixing keys passed to objects (RMap, RList, etc.) and common functions,
+	//           prefix is stripped from Keys results; commands issued with Do are not changed
+	Namespace(prefix string) Redis
+
+	// SubscribeEvents registers handler of client lifecycle events and returns function unregistering it;
+	//                 handlers are called sequentially in a background goroutine, new handler receives
+	//                 EventConnected of every reachable node first
+	SubscribeEvents(handler func(Event)) (unsubscribe func())
+
+	// Stats returns per-node pool connection counts, pub/sub connection count and command totals
+	Stats() Stats
+
+	// SlowCommands returns the last commands exceeding slow command threshold, the oldest first
+	SlowCommands() []SlowCommand
+
 	// helpers
 
+	// AnyArgs builds command arguments of key and args; strings, numbers and booleans are passed as is,
+	//         other values are encoded with codec of the client
 	AnyArgs(key string, args ...any) []string
 	StrArgs(key string, args ...string) []string
 	Do(cmd radix.Action) error
+	Codec() Codec
+
+	// Pipeline issues commands in a single round-trip
+	Pipeline(cmds ...radix.Action) error
+
+	// Transaction issues commands atomically within MULTI / EXEC in a single round-trip;
+	//             ErrTransactionAborted is returned if transaction is aborted by WATCH-ed key change
+	Transaction(cmds ...radix.Action) error
 
 	// common
 
@@ -90,6 +221,18 @@ type Redis interface {
 	Touch(keys ...string)
 	Type(key string) string
 
+	// error-returning variants of common functions
+
+	ExistsE(key ...string) (bool, error)
+	KeysE(filter string) ([]string, error)
+	TouchE(keys ...string) (int, error)
+	TypeE(key string) (string, error)
+
+	// Scan iterates keys matching glob-style pattern and type (empty - any) with SCAN instead of blocking KEYS;
+	//      count is a hint of keys per call (0 - default); cluster masters are scanned one by one and
+	//      error of a master is yielded before the next master is scanned; a key may be yielded more than once
+	Scan(ctx context.Context, match string, count int, typ string) iter.Seq2[string, error]
+
 	// basic
 
 	Set(key string, value any) error
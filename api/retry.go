@@ -0,0 +1,11 @@
+package api
+
+import "time"
+
+// RetryPolicy decides whether failed command should be issued again
+type RetryPolicy interface {
+
+	// Retry is called after failed attempt number attempt (starting with 1) of the command;
+	// it returns delay before next attempt and whether command should be retried at all
+	Retry(command string, attempt int, err error) (time.Duration, bool)
+}
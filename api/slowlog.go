@@ -0,0 +1,12 @@
+package api
+
+import "time"
+
+// SlowCommand is a command which took longer than configured threshold
+type SlowCommand struct {
+	Name     string
+	KeyCount int
+	Start    time.Time
+	Elapsed  time.Duration
+	Err      error
+}
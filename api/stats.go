@@ -0,0 +1,20 @@
+package api
+
+// Stats describes client connections and issued commands
+type Stats struct {
+	Nodes         map[string]NodeStats // Nodes are pool statistics by node address
+	PubSubConns   int                  // PubSubConns is the number of open pub/sub connections, i.e. RCacheMap subscriptions
+	Commands      int64                // Commands is the number of command attempts issued by the client
+	Errors        int64                // Errors is the number of failed command attempts
+	DroppedEvents int64                // DroppedEvents is the number of lifecycle events dropped because handlers lag behind
+}
+
+// NodeStats describes connection pool of a single node
+type NodeStats struct {
+	PoolSize int   // PoolSize is the configured pool size
+	Open     int   // Open is the number of open pool connections
+	Idle     int   // Idle is the number of open connections without commands in flight
+	InUse    int   // InUse is the number of connections with commands in flight
+	Commands int64 // Commands is the number of round-trips to the node, including pool health-check pings
+	Errors   int64 // Errors is the number of failed round-trips, including error replies
+}
@@ -0,0 +1,26 @@
+package api
+
+import "context"
+
+// Tracer starts spans for redis operations
+type Tracer interface {
+
+	// Start starts span as a child of the span carried by ctx; returned context carries the new span
+	Start(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span)
+}
+
+// Span is a traced operation
+type Span interface {
+
+	// SetAttributes adds attributes which become known after the span has started, i.e. server address
+	SetAttributes(attrs ...SpanAttribute)
+
+	// End finishes the span, non-nil err marks the span as failed
+	End(err error)
+}
+
+// SpanAttribute describes traced operation, Value is either string, int or []string
+type SpanAttribute struct {
+	Key   string
+	Value any
+}
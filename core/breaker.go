@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"go.slink.ws/redisson/api"
+	"sync"
+	"time"
+)
+
+const defaultBreakerHalfOpenRequests = 1
+
+// NewCircuitBreaker creates circuit breaker which opens after failureThreshold consecutive connection or
+// timeout errors; after openTimeout it lets probe commands through and closes on their success
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: max(failureThreshold, 1),
+		openTimeout:      openTimeout,
+		halfOpenRequests: defaultBreakerHalfOpenRequests,
+	}
+}
+
+type circuitBreaker struct {
+	mutex            sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenRequests int
+	state            api.CircuitState
+	failures         int
+	probes           int
+	openedAt         time.Time
+}
+
+// WithHalfOpenRequests limits amount of concurrent probe commands issued in half-open state
+func (b *circuitBreaker) WithHalfOpenRequests(n int) *circuitBreaker {
+	b.halfOpenRequests = max(n, 1)
+	return b
+}
+
+// allow checks whether command could be issued; it returns state transition if any
+func (b *circuitBreaker) allow() (from, to api.CircuitState, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	from = b.state
+	if b.state == api.CircuitOpen && time.Since(b.openedAt) >= b.openTimeout {
+		b.state = api.CircuitHalfOpen
+		b.probes = 0
+	}
+	switch b.state {
+	case api.CircuitOpen:
+		err = ErrCircuitOpen
+	case api.CircuitHalfOpen:
+		if b.probes >= b.halfOpenRequests {
+			err = ErrCircuitOpen
+		} else {
+			b.probes++
+		}
+	}
+	return from, b.state, err
+}
+
+// done records command result; it returns state transition if any
+func (b *circuitBreaker) done(err error) (from, to api.CircuitState) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	from = b.state
+	if !errors.Is(err, ErrConnection) && !errors.Is(err, ErrTimeout) {
+		// command reached redis
+		b.failures = 0
+		b.state = api.CircuitClosed
+		return from, b.state
+	}
+	b.failures++
+	if b.state == api.CircuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = api.CircuitOpen
+		b.openedAt = time.Now()
+	}
+	return from, b.state
+}
+
+// cancel releases probe slot of command cancelled by caller
+func (b *circuitBreaker) cancel() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == api.CircuitHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *circuitBreaker) health() api.Health {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return api.Health{
+		Circuit:  b.state,
+		Failures: b.failures,
+		OpenedAt: b.openedAt,
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testLogger struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (l *testLogger) log(level, message string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.messages = append(l.messages, level+": "+fmt.Sprintf(message, args...))
+}
+func (l *testLogger) Debug(message string, args ...interface{})   {}
+func (l *testLogger) Notice(message string, args ...interface{})  { l.log("notice", message, args...) }
+func (l *testLogger) Info(message string, args ...interface{})    { l.log("info", message, args...) }
+func (l *testLogger) Warning(message string, args ...interface{}) { l.log("warning", message, args...) }
+func (l *testLogger) Error(message string, args ...interface{})   { l.log("error", message, args...) }
+func (l *testLogger) Messages() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return append([]string(nil), l.messages...)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	b := NewCircuitBreaker(2, 50*time.Millisecond)
+
+	_, _, err := b.allow()
+	if err != nil {
+		t.Error(err)
+	}
+	if _, to := b.done(ErrConnection); to != api.CircuitClosed {
+		t.Errorf("expected '%s', received '%s'", api.CircuitClosed, to)
+	}
+	if _, to := b.done(ErrWrongType); to != api.CircuitClosed || b.health().Failures != 0 {
+		t.Errorf("expected reply errors to reset failures")
+	}
+	b.done(ErrTimeout)
+	if from, to := b.done(ErrConnection); from != api.CircuitClosed || to != api.CircuitOpen {
+		t.Errorf("expected '%s' -> '%s', received '%s' -> '%s'", api.CircuitClosed, api.CircuitOpen, from, to)
+	}
+	if _, _, err = b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected '%v', received '%v'", ErrCircuitOpen, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	from, to, err := b.allow()
+	if err != nil || from != api.CircuitOpen || to != api.CircuitHalfOpen {
+		t.Errorf("expected '%s' -> '%s', received '%s' -> '%s' (%v)", api.CircuitOpen, api.CircuitHalfOpen, from, to, err)
+	}
+	if _, _, err = b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected single probe in half-open state, received '%v'", err)
+	}
+	if _, to = b.done(ErrTimeout); to != api.CircuitOpen {
+		t.Errorf("expected failed probe to open circuit, received '%s'", to)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	_, _, _ = b.allow()
+	b.cancel()
+	_, _, err = b.allow()
+	if err != nil {
+		t.Errorf("expected cancelled probe to release its slot, received '%v'", err)
+	}
+	if _, to = b.done(nil); to != api.CircuitClosed {
+		t.Errorf("expected successful probe to close circuit, received '%s'", to)
+	}
+}
+func TestCircuitBreakerClient(t *testing.T) {
+	logger := &testLogger{}
+	r, err := NewConfig().
+		WithLogger(logger).
+		WithReadTimeout(50 * time.Millisecond).
+		WithCircuitBreaker(NewCircuitBreaker(2, 300*time.Millisecond)).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	if r.Health().Circuit != api.CircuitClosed {
+		t.Errorf("expected '%s', received '%s'", api.CircuitClosed, r.Health().Circuit)
+	}
+	for i := 0; i < 2; i++ {
+		err = r.Do(radix.Cmd(nil, "DEBUG", "SLEEP", "0.1"))
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("expected '%v', received '%v'", ErrTimeout, err)
+		}
+	}
+	health := r.Health()
+	if health.Circuit != api.CircuitOpen || health.Failures != 2 || health.OpenedAt.IsZero() {
+		t.Errorf("unexpected health: %+v", health)
+	}
+	_, err = r.WithContext(t.Context()).Get("TEST_KEY")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected '%v', received '%v'", ErrCircuitOpen, err)
+	}
+
+	time.Sleep(350 * time.Millisecond)
+	err = r.Set("TEST_KEY", "TEST_VALUE")
+	if err != nil {
+		t.Error(err)
+	}
+	if r.Health().Circuit != api.CircuitClosed {
+		t.Errorf("expected '%s', received '%s'", api.CircuitClosed, r.Health().Circuit)
+	}
+	_, _ = r.Del("TEST_KEY")
+
+	expected := []string{
+		"warning: circuit breaker state changed from closed to open",
+		"info: circuit breaker state changed from open to half-open",
+		"info: circuit breaker state changed from half-open to closed",
+	}
+	messages := logger.Messages()
+	if fmt.Sprint(messages) != fmt.Sprint(expected) {
+		t.Errorf("expected '%v', received '%v'", expected, messages)
+	}
+}
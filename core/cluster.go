@@ -0,0 +1,105 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"slices"
+	"sync"
+)
+
+// nodeContextKey holds radix client of the node command should be issued to, bypassing routing
+type nodeContextKey struct{}
+
+func withNode(ctx context.Context, client radix.Client) context.Context {
+	return context.WithValue(ctx, nodeContextKey{}, client)
+}
+func nodeFromContext(ctx context.Context) radix.Client {
+	client, _ := ctx.Value(nodeContextKey{}).(radix.Client)
+	return client
+}
+
+// forEachMaster calls fn in parallel for every master with client view issuing commands to that master;
+// errors are annotated with node address and joined
+func (r *redis) forEachMaster(fn func(client api.Redis) error) error {
+	clients, err := r.masters()
+	if err != nil {
+		return wrapError(err)
+	}
+	ctx := r.defaultContext()
+	var errs []error
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for addr, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := fn(r.WithContext(withNode(ctx, client)))
+			if err == nil {
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			errs = append(errs, fmt.Errorf("%s %s: %w", roleMaster, addr, err))
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// clusterKeys issues KEYS to every master, result is sorted and deduplicated,
+// since key may be reported by two masters while its slot is migrated
+func (r *redis) clusterKeys(pattern string) ([]string, error) {
+	var result []string
+	var mutex sync.Mutex
+	err := r.forEachMaster(func(client api.Redis) error {
+		var keys []string
+		err := client.Do(radix.Cmd(&keys, "KEYS", pattern))
+		mutex.Lock()
+		defer mutex.Unlock()
+		result = append(result, keys...)
+		return err
+	})
+	slices.Sort(result)
+	return slices.Compact(result), err
+}
+
+// countKeys issues command counting keys it is applied to; cluster keys are grouped by hash slot
+// and command is issued for every slot group in parallel, so keys of different slots do not fail with CROSSSLOT
+func (r *redis) countKeys(cmd string, keys []string) (int, error) {
+	var groups map[uint16][]string
+	if r.cluster != nil {
+		groups = make(map[uint16][]string)
+		for _, key := range keys {
+			slot := radix.ClusterSlot([]byte(key))
+			groups[slot] = append(groups[slot], key)
+		}
+	}
+	if len(groups) <= 1 {
+		var amount int
+		err := r.Do(radix.Cmd(&amount, cmd, keys...))
+		return amount, err
+	}
+	var total int
+	var errs []error
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var amount int
+			err := r.Do(radix.Cmd(&amount, cmd, group...))
+			mutex.Lock()
+			defer mutex.Unlock()
+			total += amount
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return total, errors.Join(errs...)
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/stvp/tempredis"
+	"go.slink.ws/redisson/api"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const clusterSlots = 16384
+
+// startTestCluster starts local cluster of n masters listening on ports starting from port;
+// slots are split evenly between masters
+func startTestCluster(t *testing.T, port int, n int) ([]*tempredis.Server, []string) {
+	t.Helper()
+	var servers []*tempredis.Server
+	var addrs []string
+	t.Cleanup(func() {
+		for _, s := range servers {
+			_ = s.Term()
+		}
+	})
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		s, err := tempredis.Start(tempredis.Config{
+			"port":                strconv.Itoa(port + i),
+			"cluster-enabled":     "yes",
+			"cluster-config-file": filepath.Join(t.TempDir(), "nodes.conf"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		servers = append(servers, s)
+		addrs = append(addrs, fmt.Sprintf("%s:%d", testServerHost, port+i))
+	}
+	for i, addr := range addrs {
+		conn, err := radix.Dial(ctx, "tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		from, to := i*clusterSlots/n, (i+1)*clusterSlots/n-1
+		err = conn.Do(ctx, radix.FlatCmd(nil, "CLUSTER", "ADDSLOTSRANGE", from, to))
+		for j := 0; j < n && err == nil; j++ {
+			err = conn.Do(ctx, radix.FlatCmd(nil, "CLUSTER", "MEET", testServerHost, port+j))
+		}
+		_ = conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// wait for slot assignment to be propagated to every node
+	deadline := time.Now().Add(10 * time.Second)
+	for _, addr := range addrs {
+		for {
+			var info string
+			conn, err := radix.Dial(ctx, "tcp", addr)
+			if err == nil {
+				err = conn.Do(ctx, radix.Cmd(&info, "CLUSTER", "INFO"))
+				_ = conn.Close()
+			}
+			if err == nil && strings.Contains(info, "cluster_state:ok") {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("cluster is not ready: %s %v", info, err)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	return servers, addrs
+}
+
+func TestClusterKeys(t *testing.T) {
+	servers, addrs := startTestCluster(t, testServerPort+20, 3)
+	r, err := NewConfig().NewCluster(addrs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	var expected []string
+	for i := 0; i < 30; i++ {
+		key := fmt.Sprintf("TEST_CLUSTER_KEY_%02d", i)
+		if err = r.Set(key, i); err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, key)
+	}
+	keys, err := r.KeysE("TEST_CLUSTER_KEY_*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(keys, expected) {
+		t.Errorf("expected %v, received %v", expected, keys)
+	}
+	var scanned []string
+	for key, err := range r.Scan(context.Background(), "TEST_CLUSTER_KEY_*", 5, "") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanned = append(scanned, key)
+	}
+	slices.Sort(scanned)
+	if !slices.Equal(scanned, expected) {
+		t.Errorf("expected %v, received %v", expected, scanned)
+	}
+	if keys = r.Namespace("TEST_CLUSTER_").Keys("KEY_0*"); len(keys) != 10 || keys[0] != "KEY_00" {
+		t.Errorf("unexpected namespaced keys %v", keys)
+	}
+
+	// keys of available masters are returned along with error of unavailable one
+	_ = servers[0].Term()
+	keys, err = r.KeysE("TEST_CLUSTER_KEY_*")
+	if !errors.Is(err, ErrConnection) || !strings.Contains(err.Error(), addrs[0]) {
+		t.Errorf("expected connection error of %s, received '%v'", addrs[0], err)
+	}
+	if len(keys) == 0 || len(keys) >= len(expected) {
+		t.Errorf("expected keys of available masters, received %v", keys)
+	}
+	scanned, err = scanned[:0], nil
+	for key, scanErr := range r.Scan(context.Background(), "TEST_CLUSTER_KEY_*", 0, "") {
+		if scanErr != nil {
+			err = scanErr
+			continue
+		}
+		scanned = append(scanned, key)
+	}
+	if !errors.Is(err, ErrConnection) || len(scanned) != len(keys) {
+		t.Errorf("expected %d keys and connection error, received %d '%v'", len(keys), len(scanned), err)
+	}
+}
+func TestClusterMultiKey(t *testing.T) {
+	_, addrs := startTestCluster(t, testServerPort+30, 3)
+	r, err := NewConfig().NewCluster(addrs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	keys := []string{"TEST_CLUSTER_A", "TEST_CLUSTER_B", "TEST_CLUSTER_C", "{TEST_CLUSTER_C}.D"}
+	for _, key := range keys {
+		if err = r.Set(key, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	missing := append(slices.Clone(keys), "TEST_CLUSTER_MISSING")
+	if exists, err := r.ExistsE(keys...); err != nil || !exists {
+		t.Errorf("expected existing keys, received %v '%v'", exists, err)
+	}
+	if exists, err := r.ExistsE(missing...); err != nil || exists {
+		t.Errorf("expected missing key, received %v '%v'", exists, err)
+	}
+	if amount, err := r.TouchE(missing...); err != nil || amount != len(keys) {
+		t.Errorf("expected %d touched keys, received %d '%v'", len(keys), amount, err)
+	}
+	if amount, err := r.Del(missing...); err != nil || amount != len(keys) {
+		t.Errorf("expected %d deleted keys, received %d '%v'", len(keys), amount, err)
+	}
+	if r.Exists(keys[0]) {
+		t.Errorf("expected deleted key %s", keys[0])
+	}
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"go.slink.ws/redisson/api"
+	"strconv"
+	"time"
+)
+
+var defaultCodec = NewStringCodec()
+
+// region - string codec
+
+// NewStringCodec returns codec which stores values in their string representation;
+// complex values (structs, slices, maps) can not be decoded back
+func NewStringCodec() api.Codec {
+	return &stringCodec{}
+}
+
+type stringCodec struct{}
+
+func (c *stringCodec) Encode(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	default:
+		return []byte(fmt.Sprintf("%v", value)), nil
+	}
+}
+func (c *stringCodec) Decode(data []byte, dest any) error {
+	var err error
+	s := string(data)
+	switch d := dest.(type) {
+	case *string:
+		*d = s
+	case *[]byte:
+		*d = append([]byte{}, data...)
+	case *int:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, 0)
+		*d = int(i)
+	case *int64:
+		*d, err = strconv.ParseInt(s, 10, 64)
+	case *int32:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, 32)
+		*d = int32(i)
+	case *uint:
+		var i uint64
+		i, err = strconv.ParseUint(s, 10, 0)
+		*d = uint(i)
+	case *uint64:
+		*d, err = strconv.ParseUint(s, 10, 64)
+	case *uint32:
+		var i uint64
+		i, err = strconv.ParseUint(s, 10, 32)
+		*d = uint32(i)
+	case *float64:
+		*d, err = strconv.ParseFloat(s, 64)
+	case *float32:
+		var f float64
+		f, err = strconv.ParseFloat(s, 32)
+		*d = float32(f)
+	case *bool:
+		*d, err = strconv.ParseBool(s)
+	case *time.Duration:
+		*d, err = parseDuration(s)
+	case *any:
+		*d = s
+	case encoding.TextUnmarshaler:
+		err = d.UnmarshalText(data)
+	default:
+		err = fmt.Errorf("string codec: unsupported destination type %T", dest)
+	}
+	return err
+}
+
+// endregion
+// region - json codec
+
+// NewJsonCodec returns codec which stores values as JSON documents
+func NewJsonCodec() api.Codec {
+	return &jsonCodec{}
+}
+
+type jsonCodec struct{}
+
+func (c *jsonCodec) Encode(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+func (c *jsonCodec) Decode(data []byte, dest any) error {
+	return json.Unmarshal(data, dest)
+}
+
+// endregion
+// region - gob codec
+
+// NewGobCodec returns codec which stores values in gob binary format;
+// interface-typed values should be registered with gob.Register
+func NewGobCodec() api.Codec {
+	return &gobCodec{}
+}
+
+type gobCodec struct{}
+
+func (c *gobCodec) Encode(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+func (c *gobCodec) Decode(data []byte, dest any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(dest)
+}
+
+// endregion
+// region - helpers
+
+func codecOrDefault(codec api.Codec) api.Codec {
+	if codec == nil {
+		return defaultCodec
+	}
+	return codec
+}
+
+// encodeArgs builds command arguments list of key followed by values encoded with codec
+func encodeArgs(codec api.Codec, key string, values ...any) ([]string, error) {
+	codec = codecOrDefault(codec)
+	result := make([]string, 0, len(values)+1)
+	result = append(result, key)
+	for _, v := range values {
+		data, err := codec.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, string(data))
+	}
+	return result, nil
+}
+
+// endregion
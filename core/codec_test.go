@@ -0,0 +1,86 @@
+package core
+
+import (
+	"go.slink.ws/redisson/api"
+	"testing"
+	"time"
+)
+
+type testCodecStruct struct {
+	Name  string
+	Count int
+	Tags  []string
+}
+
+func TestStringCodec(t *testing.T) {
+	codec := NewStringCodec()
+
+	data, err := codec.Encode(3.1415)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(data) != "3.1415" {
+		t.Errorf("expected '%s', received '%s'", "3.1415", data)
+	}
+
+	var f float64
+	err = codec.Decode(data, &f)
+	if err != nil {
+		t.Error(err)
+	}
+	if f != 3.1415 {
+		t.Errorf("expected '%f', received '%f'", 3.1415, f)
+	}
+
+	var i int
+	err = codec.Decode([]byte("not a number"), &i)
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err = codec.Encode(ts)
+	if err != nil {
+		t.Error(err)
+	}
+	var ts2 time.Time
+	err = codec.Decode(data, &ts2)
+	if err != nil {
+		t.Error(err)
+	}
+	if !ts.Equal(ts2) {
+		t.Errorf("expected '%v', received '%v'", ts, ts2)
+	}
+
+	var s testCodecStruct
+	err = codec.Decode([]byte("{}"), &s)
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+}
+func TestJsonCodec(t *testing.T) {
+	testStructCodec(t, NewJsonCodec())
+}
+func TestGobCodec(t *testing.T) {
+	testStructCodec(t, NewGobCodec())
+}
+
+func testStructCodec(t *testing.T, codec api.Codec) {
+	value := testCodecStruct{
+		Name:  "test",
+		Count: 3,
+		Tags:  []string{"a", "b"},
+	}
+	data, err := codec.Encode(value)
+	if err != nil {
+		t.Error(err)
+	}
+	var result testCodecStruct
+	err = codec.Decode(data, &result)
+	if err != nil {
+		t.Error(err)
+	}
+	if result.Name != value.Name || result.Count != value.Count || len(result.Tags) != len(value.Tags) {
+		t.Errorf("expected '%v', received '%v'", value, result)
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// multiKeyCommands are commands all arguments of which are keys
+var multiKeyCommands = map[string]bool{
+	"DEL": true, "EXISTS": true, "MGET": true, "PFCOUNT": true, "SDIFF": true, "SINTER": true,
+	"SUNION": true, "TOUCH": true, "UNLINK": true, "WATCH": true,
+}
+
+// newCommand describes action for interceptors and returns its arguments;
+// keys of plain commands are taken by position, radix reports the first key only for multi-key commands
+func newCommand(cmd radix.Action) (api.Command, []string) {
+	command := api.Command{
+		Action: cmd,
+	}
+	var args []string
+	command.Name, args = commandArgs(cmd)
+	if _, multi := cmd.(*multiAction); multi || command.Name == "" {
+		command.Keys = cmd.Properties().Keys
+		return command, args
+	}
+	for _, i := range keyIndexes(command.Name, args) {
+		command.Keys = append(command.Keys, args[i])
+	}
+	return command, args
+}
+
+// keyIndexes returns positions of key arguments of upper-cased command
+func keyIndexes(cmd string, args []string) []int {
+	keys := radix.DefaultActionProperties(cmd, args...).Keys
+	if len(keys) == 0 {
+		return nil
+	}
+	var indexes []int
+	if cmd == "MSET" || cmd == "MSETNX" {
+		for i := 0; i < len(args); i += 2 {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	}
+	// keys are continuous range of arguments
+	first, n := 0, len(keys)
+	switch cmd {
+	case "BITOP", "MEMORY", "XINFO", "XGROUP":
+		first = 1
+	case "XREAD", "XREADGROUP":
+		first = slices.IndexFunc(args, func(arg string) bool {
+			return strings.EqualFold(arg, "STREAMS")
+		}) + 1
+	}
+	if multiKeyCommands[cmd] {
+		n = len(args)
+	}
+	for i := first; i < first+n; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// blockingCommand reports whether command waits on server for data, like BLPOP;
+// commands do not block within transaction and blocking commands could not be pipelined
+func blockingCommand(cmd radix.Action) bool {
+	if _, multi := cmd.(*multiAction); multi {
+		return false
+	}
+	return !cmd.Properties().CanShareConn
+}
+
+// commandName extracts command name from actions created with radix.Cmd / radix.FlatCmd and pipelines,
+// empty string is returned for other actions
+func commandName(cmd radix.Action) string {
+	name, _ := commandArgs(cmd)
+	return name
+}
+
+// commandArgs extracts command name and arguments from actions created with radix.Cmd / radix.FlatCmd
+func commandArgs(cmd radix.Action) (string, []string) {
+	if multi, ok := cmd.(*multiAction); ok {
+		return multi.name, nil
+	}
+	s, ok := cmd.(fmt.Stringer)
+	if !ok {
+		return "", nil
+	}
+	// radix commands are formatted as ["NAME" "arg1" ...], name is upper-cased
+	str := strings.TrimPrefix(s.String(), "[")
+	var args []string
+	for {
+		quoted, err := strconv.QuotedPrefix(str)
+		if err != nil {
+			break
+		}
+		arg, err := strconv.Unquote(quoted)
+		if err != nil {
+			break
+		}
+		args = append(args, arg)
+		str = strings.TrimPrefix(str[len(quoted):], " ")
+	}
+	if len(args) == 0 {
+		return "", nil
+	}
+	return args[0], args[1:]
+}
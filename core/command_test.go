@@ -0,0 +1,45 @@
+package core
+
+import (
+	"github.com/mediocregopher/radix/v4"
+	"slices"
+	"testing"
+)
+
+func TestKeyIndexes(t *testing.T) {
+	for _, tc := range []struct {
+		cmd     string
+		args    []string
+		indexes []int
+	}{
+		{"PING", nil, nil},
+		{"GET", []string{"key"}, []int{0}},
+		{"SET", []string{"key", "key"}, []int{0}},
+		{"DEL", []string{"key1", "key2", "key3"}, []int{0, 1, 2}},
+		{"MSET", []string{"key1", "key2", "key2", "value"}, []int{0, 2}},
+		{"BITOP", []string{"AND", "dest", "src"}, []int{1, 2}},
+		{"MEMORY", []string{"USAGE", "key"}, []int{1}},
+		{"XREAD", []string{"COUNT", "2", "streams", "key1", "key2", "0", "0"}, []int{3, 4}},
+	} {
+		if indexes := keyIndexes(tc.cmd, tc.args); !slices.Equal(indexes, tc.indexes) {
+			t.Errorf("%s: expected %v, received %v", tc.cmd, tc.indexes, indexes)
+		}
+	}
+}
+func TestNewCommand(t *testing.T) {
+	for _, tc := range []struct {
+		action radix.Action
+		name   string
+		keys   []string
+	}{
+		{radix.Cmd(nil, "del", "key1", "key2"), "DEL", []string{"key1", "key2"}},
+		{radix.FlatCmd(nil, "MSET", "key1", 1, "key2", 2), "MSET", []string{"key1", "key2"}},
+		{radix.Cmd(nil, "SET", "key with \"quotes\"", "value"), "SET", []string{"key with \"quotes\""}},
+		{newPipelineAction([]radix.Action{radix.Cmd(nil, "GET", "key")}), pipelineCommand, []string{"key"}},
+	} {
+		command, _ := newCommand(tc.action)
+		if command.Name != tc.name || !slices.Equal(command.Keys, tc.keys) {
+			t.Errorf("expected %s %v, received %s %v", tc.name, tc.keys, command.Name, command.Keys)
+		}
+	}
+}
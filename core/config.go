@@ -2,9 +2,14 @@ package core
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"github.com/mediocregopher/radix/v4"
 	"go.slink.ws/redisson/api"
+	"net"
+	"os"
+	"slices"
 	"time"
 )
 
@@ -14,25 +19,52 @@ const defaultPoolSize = 5
 const defaultPingInterval = 5 * time.Second
 
 type config struct {
-	name         string
-	db           int
-	poolSize     int
-	pingInterval time.Duration
-	user         string
-	password     string
-	logger       api.Logger
+	name                string
+	db                  int
+	poolSize            int
+	pingInterval        time.Duration
+	user                string
+	password            string
+	credentials         api.CredentialsProvider
+	sentinelCredentials api.CredentialsProvider
+	logger              api.Logger
+	codec               api.Codec
+	tls                 *tls.Config
+	network             string
+	dialTimeout         time.Duration
+	readTimeout         time.Duration
+	writeTimeout        time.Duration
+	retryPolicy         api.RetryPolicy
+	breaker             *circuitBreaker
+	interceptors        []api.Interceptor
+	metrics             api.Metrics
+	tracer              api.Tracer
+	slowThreshold       time.Duration
+	slowLogSize         int
+	keyPrefix           string
+	addrs               []string
+	cluster             bool
+	masterName          string
+	err                 error
 }
 
 func NewConfig() *config {
 	return &config{
 		poolSize:     defaultPoolSize,
 		pingInterval: defaultPingInterval,
+		codec:        defaultCodec,
 	}
 }
+
+// WithLogger sets logger of client messages; slog.Default() is used by default, see NewSlogLogger
 func (c *config) WithLogger(logger api.Logger) *config {
 	c.logger = logger
 	return c
 }
+func (c *config) WithCodec(codec api.Codec) *config {
+	c.codec = codecOrDefault(codec)
+	return c
+}
 func (c *config) WithDb(db int) *config {
 	c.db = db
 	return c
@@ -55,79 +87,400 @@ func (c *config) WithAuth(user, password string) *config {
 	return c
 }
 
+// WithCredentialsProvider sets provider consulted for credentials on every dial (including pub/sub reconnects),
+// it takes precedence over WithAuth; sentinel connections use it unless WithSentinelCredentialsProvider is set
+func (c *config) WithCredentialsProvider(provider api.CredentialsProvider) *config {
+	c.credentials = provider
+	return c
+}
+
+// WithSentinelCredentialsProvider sets provider consulted for credentials on every sentinel dial
+func (c *config) WithSentinelCredentialsProvider(provider api.CredentialsProvider) *config {
+	c.sentinelCredentials = provider
+	return c
+}
+
+// WithNetwork sets network used by single node client: "tcp" (default), "tcp4", "tcp6" or "unix"
+func (c *config) WithNetwork(network string) *config {
+	c.network = network
+	return c
+}
+
+// WithDialTimeout limits time spent on establishing new connection
+func (c *config) WithDialTimeout(timeout time.Duration) *config {
+	c.dialTimeout = timeout
+	return c
+}
+
+// WithReadTimeout limits time spent on waiting for command reply; blocking commands like BLPOP are limited by their own timeout
+func (c *config) WithReadTimeout(timeout time.Duration) *config {
+	c.readTimeout = timeout
+	return c
+}
+
+// WithWriteTimeout limits time spent on writing command to connection
+func (c *config) WithWriteTimeout(timeout time.Duration) *config {
+	c.writeTimeout = timeout
+	return c
+}
+
+// WithRetryPolicy sets policy used to retry failed commands, see NewRetryPolicy
+func (c *config) WithRetryPolicy(policy api.RetryPolicy) *config {
+	c.retryPolicy = policy
+	return c
+}
+
+// WithCircuitBreaker makes client fail fast with ErrCircuitOpen while redis is unavailable, see NewCircuitBreaker
+func (c *config) WithCircuitBreaker(breaker *circuitBreaker) *config {
+	c.breaker = breaker
+	return c
+}
+
+// WithInterceptor adds interceptor wrapping every command issued by the client and objects created with it;
+// interceptors are called in the order they were added
+func (c *config) WithInterceptor(interceptor api.Interceptor) *config {
+	c.interceptors = append(c.interceptors, interceptor)
+	return c
+}
+
+// WithMetrics sets receiver of command, pool, pub/sub and RCacheMap measurements, see NewMetrics
+func (c *config) WithMetrics(metrics api.Metrics) *config {
+	c.metrics = metrics
+	return c
+}
+
+// WithTracer sets tracer starting span for every command, pipeline, transaction and RCacheMap sync
+func (c *config) WithTracer(tracer api.Tracer) *config {
+	c.tracer = tracer
+	return c
+}
+
+// WithSlowCommandThreshold makes client log commands taking longer than threshold and keep the last of them,
+// see api.Redis.SlowCommands
+func (c *config) WithSlowCommandThreshold(threshold time.Duration) *config {
+	c.slowThreshold = threshold
+	return c
+}
+
+// WithSlowCommandLogSize sets amount of the last slow commands kept by client, 128 by default
+func (c *config) WithSlowCommandLogSize(size int) *config {
+	c.slowLogSize = size
+	return c
+}
+
+// WithKeyPrefix makes client prefix keys of objects and common functions, see api.Redis.Namespace
+func (c *config) WithKeyPrefix(prefix string) *config {
+	c.keyPrefix = prefix
+	return c
+}
+
+// endregion
+// region - tls
+
+// WithTLS enables TLS for all redis connections (including sentinel and pub/sub ones);
+// config is copied, so it is not modified by other TLS options
+func (c *config) WithTLS(tlsConfig *tls.Config) *config {
+	c.tls = tlsConfig.Clone()
+	return c
+}
+
+// WithTLSCACertFile enables TLS and verifies server certificates against CA bundle in PEM file
+func (c *config) WithTLSCACertFile(path string) *config {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c.withError(fmt.Errorf("could not read CA bundle: %w", err))
+	}
+	return c.WithTLSCACert(data)
+}
+
+// WithTLSCACert enables TLS and verifies server certificates against PEM-encoded CA bundle
+func (c *config) WithTLSCACert(pemCerts []byte) *config {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemCerts) {
+		return c.withError(fmt.Errorf("no valid certificates found in CA bundle"))
+	}
+	c.tlsConfig().RootCAs = pool
+	return c
+}
+
+// WithTLSClientCertFile enables mutual TLS with client certificate and key from PEM files
+func (c *config) WithTLSClientCertFile(certFile, keyFile string) *config {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return c.withError(fmt.Errorf("could not load client certificate: %w", err))
+	}
+	return c.WithTLSClientCert(cert)
+}
+
+// WithTLSClientCert enables mutual TLS with given client certificate
+func (c *config) WithTLSClientCert(cert tls.Certificate) *config {
+	cfg := c.tlsConfig()
+	cfg.Certificates = append(slices.Clip(cfg.Certificates), cert)
+	return c
+}
+
+// WithTLSServerName enables TLS and sets server name (SNI) used to verify server certificate;
+// by default it is inferred from the connection address
+func (c *config) WithTLSServerName(name string) *config {
+	c.tlsConfig().ServerName = name
+	return c
+}
+
+func (c *config) tlsConfig() *tls.Config {
+	if c.tls == nil {
+		c.tls = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return c.tls
+}
+
+// endregion
+// region - clients
+
 func (c *config) NewSingle(addr string) (api.Redis, error) {
-	client, err := (radix.PoolConfig{
-		Size:         c.poolSize,
-		PingInterval: c.pingInterval,
-		Dialer: radix.Dialer{
-			CustomConn: c.customConn,
-			AuthUser:   c.user,
-			AuthPass:   c.password,
-			SelectDB:   fmt.Sprintf("%d", c.db),
-		},
-	}).New(context.Background(), "tcp", addr)
+	network := c.network
+	if network == "" {
+		network = "tcp"
+	}
+	return c.newSingle(network, addr)
+}
+
+// NewSingleUnix creates single node client connected via unix domain socket
+func (c *config) NewSingleUnix(path string) (api.Redis, error) {
+	return c.newSingle("unix", path)
+}
+func (c *config) newSingle(network, addr string) (api.Redis, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c = c.snapshot()
+	r := c.redis()
+	client, err := c.poolConfig(r).New(context.Background(), network, addr)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
-	return &redis{
-		single: client,
-		logger: c.logger,
-	}, nil
+	r.single = client
+	return r, nil
 }
 func (c *config) NewCluster(addr ...string) (api.Redis, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c = c.snapshot()
+	r := c.redis()
 	cfg := radix.ClusterConfig{
-		PoolConfig: radix.PoolConfig{
-			Size:         c.poolSize,
-			PingInterval: c.pingInterval,
-			Dialer: radix.Dialer{
-				CustomConn: c.customConn,
-				AuthUser:   c.user,
-				AuthPass:   c.password,
-				SelectDB:   fmt.Sprintf("%d", c.db),
-			},
-		},
+		PoolConfig: c.poolConfig(r),
+		Trace:      r.events.clusterTrace(),
 	}
 	client, err := cfg.New(context.Background(), addr)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
-	return &redis{
-		cluster: client,
-		logger:  c.logger,
-	}, err
+	r.cluster = client
+	return r, err
 }
 func (c *config) NewSentinel(name string, addr ...string) (api.Redis, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c = c.snapshot()
+	r := c.redis()
 	cfg := radix.SentinelConfig{
-		PoolConfig: radix.PoolConfig{
-			Size:         c.poolSize,
-			PingInterval: c.pingInterval,
-			Dialer: radix.Dialer{
-				CustomConn: c.customConn,
-				AuthUser:   c.user,
-				AuthPass:   c.password,
-				SelectDB:   fmt.Sprintf("%d", c.db),
-			},
-		},
+		PoolConfig:     c.poolConfig(r),
+		SentinelDialer: c.sentinelDialer(),
+		Trace:          r.events.sentinelTrace(),
 	}
 	client, err := cfg.New(context.Background(), name, addr)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
-	return &redis{
-		sentinel: client,
-		logger:   c.logger,
-	}, err
+	r.sentinel = client
+	r.masterName = name
+	r.sentinelDialer = cfg.SentinelDialer
+	return r, err
 }
 
+// snapshot copies config for the client being created, so that dialers of the client are not affected
+// by changes of the config made afterwards
+func (c *config) snapshot() *config {
+	cfg := *c
+	cfg.tls = c.tls.Clone()
+	cfg.interceptors = slices.Clone(c.interceptors)
+	cfg.addrs = slices.Clone(c.addrs)
+	return &cfg
+}
+
+// redis creates client with settings shared by all topologies
+func (c *config) redis() *redis {
+	logger := c.logger
+	if logger == nil {
+		logger = NewSlogLogger(nil)
+	}
+	var slow *slowLog
+	if c.slowThreshold > 0 {
+		slow = newSlowLog(c.slowThreshold, c.slowLogSize)
+	}
+	return &redis{
+		logger:       logger,
+		codec:        c.codec,
+		dialer:       c.dialer(),
+		readTimeout:  c.readTimeout,
+		retryPolicy:  c.retryPolicy,
+		breaker:      c.breaker,
+		interceptors: c.interceptors,
+		metrics:      c.metrics,
+		tracer:       c.tracer,
+		slowLog:      slow,
+		prefix:       c.keyPrefix,
+		events:       newEventBus(logger),
+		conns:        newConnTracker(c.poolSize, c.metrics),
+	}
+}
+func (c *config) poolConfig(r *redis) radix.PoolConfig {
+	return radix.PoolConfig{
+		Size:         c.poolSize,
+		PingInterval: c.pingInterval,
+		Trace:        r.events.poolTrace(),
+		Dialer: radix.Dialer{
+			CustomConn: func(ctx context.Context, network, addr string) (radix.Conn, error) {
+				conn, err := c.customConn(ctx, network, addr)
+				if err != nil {
+					return nil, err
+				}
+				return r.conns.track(addr, conn), nil
+			},
+		},
+	}
+}
+func (c *config) dialer() radix.Dialer {
+	return radix.Dialer{
+		CustomConn: c.customConn,
+	}
+}
+func (c *config) netDialer() interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+} {
+	d := &netDialer{
+		dialer: &net.Dialer{
+			Timeout: c.dialTimeout,
+		},
+		writeTimeout: c.writeTimeout,
+	}
+	if c.tls != nil {
+		d.dialer = &tls.Dialer{
+			NetDialer: d.dialer.(*net.Dialer),
+			Config:    c.tls,
+		}
+	}
+	return d
+}
+func (c *config) sentinelDialer() radix.Dialer {
+	provider := c.sentinelCredentials
+	if provider == nil {
+		provider = c.credentials
+	}
+	if provider == nil {
+		return radix.Dialer{
+			NetDialer: c.netDialer(),
+		}
+	}
+	return radix.Dialer{
+		CustomConn: func(ctx context.Context, network, addr string) (radix.Conn, error) {
+			user, password, err := provider(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrCredentials, err)
+			}
+			dialer := radix.Dialer{
+				AuthUser:  user,
+				AuthPass:  password,
+				NetDialer: c.netDialer(),
+			}
+			return dialer.Dial(ctx, network, addr)
+		},
+	}
+}
 func (c *config) customConn(ctx context.Context, network, addr string) (radix.Conn, error) {
-	cl, err := radix.Dial(ctx, network, addr)
+	user, password := c.user, c.password
+	if c.credentials != nil {
+		var err error
+		user, password, err = c.credentials(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCredentials, err)
+		}
+	}
+	dialer := radix.Dialer{
+		AuthUser:  user,
+		AuthPass:  password,
+		NetDialer: c.netDialer(),
+	}
+	if c.db != 0 {
+		dialer.SelectDB = fmt.Sprintf("%d", c.db)
+	}
+	cl, err := dialer.Dial(ctx, network, addr)
 	if err != nil {
 		return nil, err
 	}
 	if c.name != "" {
 		err = cl.Do(ctx, radix.Cmd(nil, "CLIENT", "SETNAME", c.name))
+		if err != nil {
+			_ = cl.Close()
+			return nil, err
+		}
+	}
+	return cl, nil
+}
+func (c *config) withError(err error) *config {
+	if c.err == nil {
+		c.err = err
+	}
+	return c
+}
+
+// endregion
+// region - net dialer
+
+// netDialer establishes network connections applying write timeout to every write
+type netDialer struct {
+	dialer interface {
+		DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+	}
+	writeTimeout time.Duration
+}
+
+func (d *netDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := d.dialer.DialContext(ctx, network, addr)
+	if err != nil || d.writeTimeout <= 0 {
+		return conn, err
+	}
+	return &deadlineConn{
+		Conn:         conn,
+		writeTimeout: d.writeTimeout,
+	}, nil
+}
+
+type deadlineConn struct {
+	net.Conn
+	writeTimeout time.Duration
+}
+
+func (c *deadlineConn) Write(b []byte) (int, error) {
+	if err := c.Conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
+func (c *deadlineConn) SetKeepAlive(keepalive bool) error {
+	if kc, ok := c.Conn.(interface{ SetKeepAlive(bool) error }); ok {
+		return kc.SetKeepAlive(keepalive)
+	}
+	return nil
+}
+func (c *deadlineConn) SetKeepAlivePeriod(period time.Duration) error {
+	if kc, ok := c.Conn.(interface{ SetKeepAlivePeriod(time.Duration) error }); ok {
+		return kc.SetKeepAlivePeriod(period)
 	}
-	return cl, err
+	return nil
 }
 
 // endregion
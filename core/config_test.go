@@ -47,4 +47,18 @@ func TestConfig(t *testing.T) {
 		t.Errorf("expected '%s', received '%s'", testPass, cfg.password)
 	}
 
+	if cfg.codec != defaultCodec {
+		t.Errorf("expected default codec, received '%T'", cfg.codec)
+	}
+	codec := NewJsonCodec()
+	cfg.WithCodec(codec)
+	if cfg.codec != codec {
+		t.Errorf("expected '%T', received '%T'", codec, cfg.codec)
+	}
+
+	cfg.WithDialTimeout(time.Second).WithReadTimeout(2 * time.Second).WithWriteTimeout(3 * time.Second)
+	if cfg.dialTimeout != time.Second || cfg.readTimeout != 2*time.Second || cfg.writeTimeout != 3*time.Second {
+		t.Errorf("unexpected timeouts: %v / %v / %v", cfg.dialTimeout, cfg.readTimeout, cfg.writeTimeout)
+	}
+
 }
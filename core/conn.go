@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"sync"
+	"sync/atomic"
+)
+
+// connTracker counts connections of client pools per node, pub/sub connections and issued commands
+type connTracker struct {
+	poolSize int
+	metrics  api.Metrics
+	mutex    sync.Mutex
+	nodes    map[string]*nodeConns
+	pubSubs  atomic.Int32
+	commands atomic.Int64
+	errors   atomic.Int64
+}
+
+type nodeConns struct {
+	open     int
+	inUse    int
+	commands int64
+	errors   int64
+}
+
+func newConnTracker(poolSize int, metrics api.Metrics) *connTracker {
+	return &connTracker{
+		poolSize: poolSize,
+		metrics:  metrics,
+		nodes:    make(map[string]*nodeConns),
+	}
+}
+
+func (t *connTracker) track(addr string, conn radix.Conn) radix.Conn {
+	t.update(addr, 1, 0)
+	return &trackedConn{
+		Conn:    conn,
+		addr:    addr,
+		tracker: t,
+	}
+}
+func (t *connTracker) trackPubSub(conn radix.PubSubConn) radix.PubSubConn {
+	t.pubSubs.Add(1)
+	return &trackedPubSubConn{
+		PubSubConn: conn,
+		tracker:    t,
+	}
+}
+func (t *connTracker) update(addr string, open, inUse int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	node := t.node(addr)
+	node.open += open
+	node.inUse += inUse
+	if t.metrics != nil {
+		t.metrics.PoolChanged(addr, t.poolSize, node.open, node.inUse)
+	}
+}
+
+// roundTrip counts command sent to node
+func (t *connTracker) roundTrip(addr string, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	node := t.node(addr)
+	node.commands++
+	if err != nil {
+		node.errors++
+	}
+}
+
+// commandDone counts command attempt issued by client
+func (t *connTracker) commandDone(err error) {
+	t.commands.Add(1)
+	if err != nil {
+		t.errors.Add(1)
+	}
+}
+func (t *connTracker) stats() api.Stats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	stats := api.Stats{
+		Nodes:       make(map[string]api.NodeStats, len(t.nodes)),
+		PubSubConns: int(t.pubSubs.Load()),
+		Commands:    t.commands.Load(),
+		Errors:      t.errors.Load(),
+	}
+	for addr, node := range t.nodes {
+		stats.Nodes[addr] = api.NodeStats{
+			PoolSize: t.poolSize,
+			Open:     node.open,
+			Idle:     node.open - node.inUse,
+			InUse:    node.inUse,
+			Commands: node.commands,
+			Errors:   node.errors,
+		}
+	}
+	return stats
+}
+
+// node returns counters of node, should be called with mutex held
+func (t *connTracker) node(addr string) *nodeConns {
+	node, ok := t.nodes[addr]
+	if !ok {
+		node = &nodeConns{}
+		t.nodes[addr] = node
+	}
+	return node
+}
+
+// trackedConn reports connection as being in use while it has commands in flight
+type trackedConn struct {
+	radix.Conn
+	addr     string
+	tracker  *connTracker
+	inFlight atomic.Int32
+	closed   sync.Once
+}
+
+func (c *trackedConn) Do(ctx context.Context, action radix.Action) error {
+	return action.Perform(ctx, c)
+}
+func (c *trackedConn) EncodeDecode(ctx context.Context, m, u interface{}) (err error) {
+	traceServer(ctx, c.addr)
+	if c.inFlight.Add(1) == 1 {
+		c.tracker.update(c.addr, 0, 1)
+	}
+	defer func() {
+		if c.inFlight.Add(-1) == 0 {
+			c.tracker.update(c.addr, 0, -1)
+		}
+		c.tracker.roundTrip(c.addr, err)
+	}()
+	return c.Conn.EncodeDecode(ctx, m, u)
+}
+func (c *trackedConn) Close() error {
+	c.closed.Do(func() {
+		c.tracker.update(c.addr, -1, 0)
+	})
+	return c.Conn.Close()
+}
+
+// trackedPubSubConn is counted as open pub/sub connection until it is closed
+type trackedPubSubConn struct {
+	radix.PubSubConn
+	tracker *connTracker
+	closed  sync.Once
+}
+
+func (c *trackedPubSubConn) Close() error {
+	c.closed.Do(func() {
+		c.tracker.pubSubs.Add(-1)
+	})
+	return c.PubSubConn.Close()
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	addr := fmt.Sprintf("%s:%d", testServerHost, testServerPort)
+	r, err := NewConfig().
+		WithPoolSize(3).
+		NewSingle(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	stats := r.Stats()
+	node, ok := stats.Nodes[addr]
+	// pool is filled in background
+	if !ok || node.PoolSize != 3 || node.Open < 1 || node.Idle != node.Open || node.InUse != 0 {
+		t.Fatalf("unexpected node stats %+v", stats.Nodes)
+	}
+
+	_ = r.Set("TEST_STATS_KEY", "value")
+	_ = r.Do(radix.Cmd(nil, "NO_SUCH_COMMAND"))
+	m, err := NewRCacheMap("TEST_STATS_CACHE_MAP", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats = r.Stats()
+	if stats.Commands < 2 || stats.Errors != 1 {
+		t.Errorf("unexpected command totals %d / %d", stats.Commands, stats.Errors)
+	}
+	if node := stats.Nodes[addr]; node.Commands < 2 || node.Errors != 1 {
+		t.Errorf("unexpected node command totals %+v", node)
+	}
+	if stats.PubSubConns != 1 {
+		t.Errorf("expected 1 pub/sub connection, got %d", stats.PubSubConns)
+	}
+	m.Destroy()
+	if stats = r.Stats(); stats.PubSubConns != 0 {
+		t.Errorf("expected no pub/sub connections, got %d", stats.PubSubConns)
+	}
+	_, _ = r.Del("TEST_STATS_KEY")
+}
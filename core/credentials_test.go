@@ -0,0 +1,115 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/stvp/tempredis"
+	"go.slink.ws/redisson/api"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// testCredentials is rotated credentials storage
+type testCredentials struct {
+	mutex    sync.Mutex
+	user     string
+	password string
+	calls    int
+}
+
+func (c *testCredentials) provide(context.Context) (string, string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.calls++
+	return c.user, c.password, nil
+}
+func (c *testCredentials) rotate(password string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.password = password
+}
+func (c *testCredentials) Calls() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.calls
+}
+
+func TestCredentialsProvider(t *testing.T) {
+	const port = testServerPort + 12
+	addr := fmt.Sprintf("%s:%d", testServerHost, port)
+	s, err := tempredis.Start(tempredis.Config{
+		"port":        strconv.Itoa(port),
+		"requirepass": "TEST_ADMIN_PASSWORD",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Term()
+	}()
+	admin, err := NewConfig().
+		WithAuth("default", "TEST_ADMIN_PASSWORD").
+		NewSingle(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(admin)
+	err = admin.Do(radix.Cmd(nil, "ACL", "SETUSER", "app", "on", ">TEST_PASSWORD_1", "~*", "&*", "+@all"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credentials := &testCredentials{user: "app", password: "TEST_PASSWORD_1"}
+	r, err := NewConfig().
+		WithPoolSize(1).
+		WithCredentialsProvider(credentials.provide).
+		NewSingle(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	if err = r.Set("TEST_CREDENTIALS_KEY", "value"); err != nil {
+		t.Fatal(err)
+	}
+
+	// rotation: the old password stops working for new connections
+	err = admin.Do(radix.Cmd(nil, "ACL", "SETUSER", "app", "resetpass", ">TEST_PASSWORD_2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials.rotate("TEST_PASSWORD_2")
+	calls := credentials.Calls()
+	ps, err := r.PubSub()
+	if err != nil {
+		t.Fatalf("expected pub/sub connection with rotated password: %v", err)
+	}
+	_ = ps.Close()
+	if credentials.Calls() <= calls {
+		t.Errorf("expected credentials to be requested on dial")
+	}
+	if _, err = r.Get("TEST_CREDENTIALS_KEY"); err != nil {
+		t.Errorf("expected existing connections to keep working: %v", err)
+	}
+
+	_, err = NewConfig().
+		WithAuth("app", "TEST_PASSWORD_1").
+		NewSingle(addr)
+	if !errors.Is(err, ErrAuth) {
+		t.Errorf("expected '%v', received '%v'", ErrAuth, err)
+	}
+	_, err = NewConfig().
+		WithCredentialsProvider(func(context.Context) (string, string, error) {
+			return "", "", errors.New("vault is sealed")
+		}).
+		NewSingle(addr)
+	if !errors.Is(err, ErrCredentials) {
+		t.Errorf("expected '%v', received '%v'", ErrCredentials, err)
+	}
+}
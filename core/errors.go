@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4/resp/resp3"
+	"io"
+	"net"
+	"strings"
+)
+
+var ErrRedisClientNotInitialized = errors.New("redis client is not initialized")
+var ErrNotFound = errors.New("redis key not found")
+var ErrInvalidURL = errors.New("invalid redis url")
+var ErrCircuitOpen = errors.New("redis circuit breaker is open")
+var ErrTransactionAborted = errors.New("redis transaction aborted")
+var ErrNoQuorum = errors.New("redis sentinel quorum is not reached")
+var ErrCredentials = errors.New("could not get redis credentials")
+
+// error classes; errors returned by the client wrap both error class and original radix error,
+// so errors.Is / errors.As work for either of them
+var (
+	ErrConnection = errors.New("redis connection error")
+	ErrTimeout    = errors.New("redis timeout")
+)
+
+// reply error classes, see https://redis.io/docs/reference/protocol-spec/#simple-errors
+var (
+	ErrWrongType = errors.New("redis wrong type")
+	ErrNoScript  = errors.New("redis script not found")
+	ErrMoved     = errors.New("redis slot moved")
+	ErrAsk       = errors.New("redis slot migrating")
+	ErrReadOnly  = errors.New("redis replica is read-only")
+	ErrLoading   = errors.New("redis is loading dataset")
+	ErrBusy      = errors.New("redis is busy running a script")
+	ErrAuth      = errors.New("redis authentication failed")
+)
+
+var replyErrorClasses = map[string]error{
+	"WRONGTYPE": ErrWrongType,
+	"NOSCRIPT":  ErrNoScript,
+	"MOVED":     ErrMoved,
+	"ASK":       ErrAsk,
+	"READONLY":  ErrReadOnly,
+	"LOADING":   ErrLoading,
+	"BUSY":      ErrBusy,
+	"NOAUTH":    ErrAuth,
+	"WRONGPASS": ErrAuth,
+	"NOPERM":    ErrAuth,
+}
+
+// ReplyError is an error reply received from redis
+type ReplyError struct {
+	// Prefix is the first word of error reply, i.e. "ERR", "WRONGTYPE", "MOVED"
+	Prefix string
+	// Message is the rest of error reply
+	Message string
+	err     error
+}
+
+func newReplyError(reply string, err error) *ReplyError {
+	prefix, message, _ := strings.Cut(reply, " ")
+	return &ReplyError{
+		Prefix:  prefix,
+		Message: message,
+		err:     err,
+	}
+}
+func (e *ReplyError) Error() string {
+	if e.Message == "" {
+		return e.Prefix
+	}
+	return e.Prefix + " " + e.Message
+}
+
+// Class returns sentinel error for reply prefix (ErrWrongType, ErrMoved, etc.) or nil for generic errors
+func (e *ReplyError) Class() error {
+	return replyErrorClasses[e.Prefix]
+}
+func (e *ReplyError) Unwrap() []error {
+	if class := e.Class(); class != nil {
+		return []error{class, e.err}
+	}
+	return []error{e.err}
+}
+
+// wrapError annotates radix error with its error class
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var replyErr *ReplyError
+	if errors.As(err, &replyErr) {
+		return err
+	}
+	var simpleErr resp3.SimpleError
+	if errors.As(err, &simpleErr) {
+		return newReplyError(simpleErr.S, err)
+	}
+	var blobErr resp3.BlobError
+	if errors.As(err, &blobErr) {
+		return newReplyError(string(blobErr.B), err)
+	}
+	if class := errorClass(err); class != nil && !errors.Is(err, class) {
+		return fmt.Errorf("%w: %w", class, err)
+	}
+	return err
+}
+func errorClass(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrTimeout
+		}
+		return ErrConnection
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) {
+		return ErrConnection
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/mediocregopher/radix/v4/resp"
+	"github.com/mediocregopher/radix/v4/resp/resp3"
+	"go.slink.ws/redisson/api"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWrapError(t *testing.T) {
+	if wrapError(nil) != nil {
+		t.Errorf("expected nil error")
+	}
+
+	replyErr := resp.ErrConnUsable{Err: resp3.SimpleError{S: "WRONGTYPE Operation against a key holding the wrong kind of value"}}
+	err := wrapError(replyErr)
+	if !errors.Is(err, ErrWrongType) {
+		t.Errorf("expected '%v', received '%v'", ErrWrongType, err)
+	}
+	var simpleErr resp3.SimpleError
+	if !errors.As(err, &simpleErr) {
+		t.Errorf("expected wrapped radix error, received '%v'", err)
+	}
+
+	err = wrapError(context.DeadlineExceeded)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected '%v', received '%v'", ErrTimeout, err)
+	}
+
+	err = wrapError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+	if !errors.Is(err, ErrConnection) {
+		t.Errorf("expected '%v', received '%v'", ErrConnection, err)
+	}
+
+	err = wrapError(io.EOF)
+	if !errors.Is(err, ErrConnection) || !errors.Is(err, io.EOF) {
+		t.Errorf("expected '%v', received '%v'", ErrConnection, err)
+	}
+
+	err = errors.New("other error")
+	if wrapError(err) != err {
+		t.Errorf("expected unchanged error, received '%v'", wrapError(err))
+	}
+}
+func TestReplyError(t *testing.T) {
+	testData := map[string]error{
+		"NOSCRIPT No matching script. Please use EVAL.":               ErrNoScript,
+		"MOVED 3999 127.0.0.1:6381":                                   ErrMoved,
+		"ASK 3999 127.0.0.1:6381":                                     ErrAsk,
+		"READONLY You can't write against a read only replica.":       ErrReadOnly,
+		"LOADING Redis is loading the dataset in memory":              ErrLoading,
+		"BUSY Redis is busy running a script.":                        ErrBusy,
+		"NOAUTH Authentication required.":                             ErrAuth,
+		"WRONGPASS invalid username-password pair":                    ErrAuth,
+		"WRONGTYPE Operation against a key holding the wrong kind of": ErrWrongType,
+	}
+	for reply, class := range testData {
+		err := wrapError(resp.ErrConnUsable{Err: resp3.SimpleError{S: reply}})
+		if !errors.Is(err, class) {
+			t.Errorf("expected '%v', received '%v'", class, err)
+		}
+		var replyErr *ReplyError
+		if !errors.As(err, &replyErr) {
+			t.Errorf("expected reply error, received '%v'", err)
+			continue
+		}
+		if replyErr.Error() != reply {
+			t.Errorf("expected '%s', received '%s'", reply, replyErr.Error())
+		}
+	}
+
+	err := wrapError(resp3.SimpleError{S: "ERR unknown command 'FOO'"})
+	var replyErr *ReplyError
+	if !errors.As(err, &replyErr) {
+		t.Errorf("expected reply error, received '%v'", err)
+	} else {
+		if replyErr.Prefix != "ERR" {
+			t.Errorf("expected '%s', received '%s'", "ERR", replyErr.Prefix)
+		}
+		if replyErr.Message != "unknown command 'FOO'" {
+			t.Errorf("expected '%s', received '%s'", "unknown command 'FOO'", replyErr.Message)
+		}
+		if replyErr.Class() != nil {
+			t.Errorf("expected no error class, received '%v'", replyErr.Class())
+		}
+	}
+	if wrapError(err) != err {
+		t.Errorf("expected unchanged error, received '%v'", wrapError(err))
+	}
+}
+func TestErrorClasses(t *testing.T) {
+
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	_ = r.Set("TEST_KEY", "TEST_VALUE")
+
+	_, err = NewRList("TEST_KEY", r).LenE()
+	if !errors.Is(err, ErrWrongType) {
+		t.Errorf("expected '%v', received '%v'", ErrWrongType, err)
+	}
+	_, err = NewRSet("TEST_KEY", r).ItemsE()
+	if !errors.Is(err, ErrWrongType) {
+		t.Errorf("expected '%v', received '%v'", ErrWrongType, err)
+	}
+	_, err = NewRMap("TEST_KEY", r).EntriesE()
+	if !errors.Is(err, ErrWrongType) {
+		t.Errorf("expected '%v', received '%v'", ErrWrongType, err)
+	}
+	value, ok, err := NewRMap("TEST_KEY", r).GetE("field")
+	if !errors.Is(err, ErrWrongType) || ok || !value.IsNil() {
+		t.Errorf("expected '%v', received '%v' %v '%v'", ErrWrongType, value, ok, err)
+	}
+
+	err = r.Do(radix.Cmd(nil, "UNKNOWN_COMMAND"))
+	var replyErr *ReplyError
+	if !errors.As(err, &replyErr) || replyErr.Prefix != "ERR" {
+		t.Errorf("expected 'ERR' reply error, received '%v'", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	_, err = r.WithContext(ctx).ExistsE("TEST_KEY")
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected '%v', received '%v'", ErrTimeout, err)
+	}
+
+	_, _ = r.Del("TEST_KEY")
+
+}
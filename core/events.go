@@ -0,0 +1,203 @@
+package core
+
+import (
+	"context"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/mediocregopher/radix/v4/trace"
+	"go.slink.ws/redisson/api"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// region - event bus
+
+// eventQueueSize limits events waiting for delivery; events are dropped while handlers lag behind
+const eventQueueSize = 128
+
+type eventHandler struct {
+	id      int
+	handler func(api.Event)
+}
+
+// queuedEvent is event waiting for delivery to all handlers or, if handler id is set, to the single one
+type queuedEvent struct {
+	event   api.Event
+	handler int
+}
+
+// eventBus delivers client lifecycle events to handlers in a background goroutine,
+// so handlers could issue commands without blocking radix internals
+type eventBus struct {
+	mutex    sync.Mutex
+	handlers []eventHandler
+	nextID   int
+	queue    chan queuedEvent
+	started  sync.Once
+	closed   sync.Once
+	doneChn  chan struct{}
+	nodes    map[string]bool // reachability of nodes by address
+	primary  string          // the last known sentinel master
+	dropped  atomic.Int64    // events dropped because queue is full
+	logger   api.Logger
+}
+
+func newEventBus(logger api.Logger) *eventBus {
+	return &eventBus{
+		queue:   make(chan queuedEvent, eventQueueSize),
+		doneChn: make(chan struct{}),
+		nodes:   make(map[string]bool),
+		logger:  logger,
+	}
+}
+
+// subscribe registers handler; handler receives EventConnected for every node reachable at the moment first,
+// since nodes usually connect before handlers are registered
+func (b *eventBus) subscribe(handler func(api.Event)) func() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.handlers = append(b.handlers, eventHandler{id: id, handler: handler})
+	b.started.Do(func() {
+		go b.run()
+	})
+	var addrs []string
+	for addr, reachable := range b.nodes {
+		if reachable {
+			addrs = append(addrs, addr)
+		}
+	}
+	slices.Sort(addrs)
+	for _, addr := range addrs {
+		b.enqueue(queuedEvent{event: api.Event{Type: api.EventConnected, Addr: addr, Time: time.Now()}, handler: id})
+	}
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		b.handlers = slices.DeleteFunc(b.handlers, func(h eventHandler) bool {
+			return h.id == id
+		})
+	}
+}
+func (b *eventBus) publish(eventType api.EventType, addr string, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.publishLocked(eventType, addr, err)
+}
+
+// publishLocked queues event for registered handlers, caller holds the mutex
+func (b *eventBus) publishLocked(eventType api.EventType, addr string, err error) {
+	if len(b.handlers) == 0 {
+		return
+	}
+	b.enqueue(queuedEvent{event: api.Event{Type: eventType, Addr: addr, Err: err, Time: time.Now()}})
+}
+
+// enqueue queues event without blocking radix internals, event is dropped if queue is full
+func (b *eventBus) enqueue(event queuedEvent) {
+	select {
+	case b.queue <- event:
+	default:
+		dropped := b.dropped.Add(1)
+		if b.logger != nil {
+			b.logger.Warning("lifecycle event '%s' of '%s' is dropped as handlers lag behind, %d events dropped in total",
+				event.event.Type, event.event.Addr, dropped)
+		}
+	}
+}
+func (b *eventBus) run() {
+	for {
+		select {
+		case <-b.doneChn:
+			return
+		case event := <-b.queue:
+			b.mutex.Lock()
+			handlers := slices.Clone(b.handlers)
+			b.mutex.Unlock()
+			for _, h := range handlers {
+				if event.handler == 0 || event.handler == h.id {
+					h.handler(event.event)
+				}
+			}
+		}
+	}
+}
+func (b *eventBus) close() {
+	b.closed.Do(func() {
+		close(b.doneChn)
+	})
+}
+
+// endregion
+// region - radix traces
+
+// poolTrace reports node as connected on the first connection, as disconnected when connection
+// could not be established and as reconnected when it is established again
+func (b *eventBus) poolTrace() trace.PoolTrace {
+	return trace.PoolTrace{
+		ConnCreated: func(e trace.PoolConnCreated) {
+			// node state and its event are changed together, so subscriber does not receive both event and replay
+			b.mutex.Lock()
+			defer b.mutex.Unlock()
+			reachable, known := b.nodes[e.Addr]
+			b.nodes[e.Addr] = e.Err == nil
+			switch {
+			case e.Err == nil && !known:
+				b.publishLocked(api.EventConnected, e.Addr, nil)
+			case e.Err == nil && !reachable:
+				b.publishLocked(api.EventReconnected, e.Addr, nil)
+			case e.Err != nil && reachable:
+				b.publishLocked(api.EventDisconnected, e.Addr, wrapError(e.Err))
+			}
+		},
+	}
+}
+func (b *eventBus) clusterTrace() trace.ClusterTrace {
+	return trace.ClusterTrace{
+		TopoChanged: func(trace.ClusterTopoChanged) {
+			b.publish(api.EventTopologyChanged, "", nil)
+		},
+	}
+}
+
+// sentinelTrace reports master change as failover, other changes as topology change
+func (b *eventBus) sentinelTrace() trace.SentinelTrace {
+	return trace.SentinelTrace{
+		TopoChanged: func(e trace.SentinelTopoChanged) {
+			primary := ""
+			for _, node := range slices.Concat(e.Added, e.Changed) {
+				if node.IsPrimary {
+					primary = node.Addr
+				}
+			}
+			b.mutex.Lock()
+			previous := b.primary
+			if primary != "" {
+				b.primary = primary
+			}
+			b.mutex.Unlock()
+			if primary != "" && previous != "" && primary != previous {
+				b.publish(api.EventFailover, primary, nil)
+			} else {
+				b.publish(api.EventTopologyChanged, "", nil)
+			}
+		},
+	}
+}
+
+// pubSubDialer reports re-established pub/sub connection, subscriptions are restored by radix right after dial
+func (b *eventBus) pubSubDialer(dialer radix.Dialer, reconnecting *atomic.Bool) radix.Dialer {
+	customConn := dialer.CustomConn
+	dialer.CustomConn = func(ctx context.Context, network, addr string) (radix.Conn, error) {
+		conn, err := customConn(ctx, network, addr)
+		if err == nil && reconnecting.Swap(false) {
+			b.publish(api.EventPubSubResubscribed, addr, nil)
+		}
+		return conn, err
+	}
+	return dialer
+}
+
+// endregion
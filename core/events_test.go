@@ -0,0 +1,170 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4/trace"
+	"github.com/stvp/tempredis"
+	"go.slink.ws/redisson/api"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// eventRecorder collects events delivered to handler
+type eventRecorder chan api.Event
+
+func (r eventRecorder) handle(event api.Event) {
+	r <- event
+}
+func (r eventRecorder) expect(t *testing.T, eventType api.EventType, addr string) api.Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-r:
+			if event.Type == eventType && (addr == "" || event.Addr == addr) {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("expected '%s' event", eventType)
+			return api.Event{}
+		}
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	b := newEventBus(nil)
+	defer b.close()
+	b.publish(api.EventConnected, "ignored", nil)
+
+	events := make(eventRecorder, 10)
+	unsubscribe := b.subscribe(events.handle)
+	pool := b.poolTrace()
+	pool.ConnCreated(trace.PoolConnCreated{PoolCommon: trace.PoolCommon{Addr: "a"}})
+	pool.ConnCreated(trace.PoolConnCreated{PoolCommon: trace.PoolCommon{Addr: "a"}})
+	pool.ConnCreated(trace.PoolConnCreated{PoolCommon: trace.PoolCommon{Addr: "a"}, Err: errors.New("failure")})
+	pool.ConnCreated(trace.PoolConnCreated{PoolCommon: trace.PoolCommon{Addr: "a"}, Err: errors.New("failure")})
+	pool.ConnCreated(trace.PoolConnCreated{PoolCommon: trace.PoolCommon{Addr: "a"}})
+
+	sentinel := b.sentinelTrace()
+	sentinel.TopoChanged(trace.SentinelTopoChanged{Added: []trace.SentinelNodeInfo{{Addr: "a", IsPrimary: true}}})
+	sentinel.TopoChanged(trace.SentinelTopoChanged{Added: []trace.SentinelNodeInfo{{Addr: "c"}}})
+	sentinel.TopoChanged(trace.SentinelTopoChanged{Changed: []trace.SentinelNodeInfo{{Addr: "b", IsPrimary: true}}})
+
+	expected := []api.EventType{
+		api.EventConnected, api.EventDisconnected, api.EventReconnected,
+		api.EventTopologyChanged, api.EventTopologyChanged, api.EventFailover,
+	}
+	for _, eventType := range expected {
+		select {
+		case event := <-events:
+			if event.Type != eventType {
+				t.Errorf("expected '%s', received '%s'", eventType, event.Type)
+			}
+			if event.Type == api.EventFailover && event.Addr != "b" {
+				t.Errorf("expected failover to 'b', received '%s'", event.Addr)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected '%s' event", eventType)
+		}
+	}
+
+	unsubscribe()
+	b.clusterTrace().TopoChanged(trace.ClusterTopoChanged{})
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event after unsubscribe: %v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// handler registered after nodes are connected receives their connection events
+	replayed := make(eventRecorder, 10)
+	defer b.subscribe(replayed.handle)()
+	replayed.expect(t, api.EventConnected, "a")
+}
+func TestEventBusQueueFull(t *testing.T) {
+	logger := &testLogger{}
+	b := newEventBus(logger)
+	defer b.close()
+	release := make(chan struct{})
+	defer b.subscribe(func(api.Event) {
+		<-release
+	})()
+
+	// the first event is taken by blocked handler, the next ones fill the queue
+	for i := 0; i < eventQueueSize+3; i++ {
+		b.publish(api.EventTopologyChanged, "", nil)
+	}
+	close(release)
+	if dropped := b.dropped.Load(); dropped < 2 || dropped > 3 {
+		t.Errorf("expected 2 or 3 dropped events, received %d", dropped)
+	}
+	if messages := logger.Messages(); len(messages) != int(b.dropped.Load()) {
+		t.Errorf("expected warning of every dropped event, received '%v'", messages)
+	}
+}
+func TestEventsReconnect(t *testing.T) {
+	const port = testServerPort + 11
+	addr := fmt.Sprintf("%s:%d", testServerHost, port)
+	cfg := tempredis.Config{"port": strconv.Itoa(port)}
+	s, err := tempredis.Start(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Term()
+	}()
+	r, err := NewConfig().
+		WithPingInterval(100 * time.Millisecond).
+		NewSingle(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	events := make(eventRecorder, 100)
+	defer r.SubscribeEvents(events.handle)()
+	// node is connected before handler is registered
+	events.expect(t, api.EventConnected, addr)
+
+	ps, err := r.PubSub()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = ps.Close()
+	}()
+	err = ps.Subscribe(context.Background(), "TEST_EVENTS_CHANNEL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = s.Term()
+	event := events.expect(t, api.EventDisconnected, addr)
+	if !errors.Is(event.Err, ErrConnection) {
+		t.Errorf("expected '%v', received '%v'", ErrConnection, event.Err)
+	}
+	s, err = tempredis.Start(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events.expect(t, api.EventReconnected, addr)
+
+	// broken pub/sub connection is noticed on use, the first write to closed connection may succeed
+	timeout := time.After(5 * time.Second)
+	for {
+		_ = ps.Ping(context.Background())
+		select {
+		case event := <-events:
+			if event.Type == api.EventPubSubResubscribed {
+				return
+			}
+		case <-time.After(50 * time.Millisecond):
+		case <-timeout:
+			t.Fatalf("expected '%s' event", api.EventPubSubResubscribed)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	roleMaster   = "master"
+	roleReplica  = "replica"
+	roleSentinel = "sentinel"
+)
+
+// defaultHealthCheckTimeout bounds checks issued with context without deadline:
+// pool of unavailable node waits for connection until context is done
+const defaultHealthCheckTimeout = 5 * time.Second
+
+func (r *redis) Ping(ctx context.Context) error {
+	var errs []error
+	for _, node := range r.checkMasters(ctx) {
+		if node.Err != nil {
+			errs = append(errs, nodeError(node))
+		}
+	}
+	return errors.Join(errs...)
+}
+func (r *redis) Health() api.Health {
+	if r.breaker == nil {
+		return api.Health{Circuit: api.CircuitClosed}
+	}
+	return r.breaker.health()
+}
+func (r *redis) CheckHealth(ctx context.Context) api.HealthReport {
+	report := api.HealthReport{Health: r.Health()}
+	var errs []error
+	report.Nodes = r.checkMasters(ctx)
+	for _, node := range report.Nodes {
+		if node.Err != nil {
+			errs = append(errs, nodeError(node))
+		}
+	}
+	if r.sentinel != nil {
+		sentinels, err := r.checkSentinels(ctx)
+		report.Nodes = append(report.Nodes, sentinels...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	report.Err = errors.Join(errs...)
+	return report
+}
+
+// masters returns clients of master nodes by address
+func (r *redis) masters() (map[string]radix.Client, error) {
+	if r.single != nil {
+		return map[string]radix.Client{r.single.Addr().String(): r.single}, nil
+	}
+	var replicaSets map[string]radix.ReplicaSet
+	var err error
+	if r.cluster != nil {
+		replicaSets, err = r.cluster.Clients()
+	} else if r.sentinel != nil {
+		replicaSets, err = r.sentinel.Clients()
+	} else {
+		return nil, ErrRedisClientNotInitialized
+	}
+	if err != nil {
+		return nil, err
+	}
+	clients := make(map[string]radix.Client, len(replicaSets))
+	for addr, rs := range replicaSets {
+		clients[addr] = rs.Primary
+	}
+	return clients, nil
+}
+
+// checkMasters pings master nodes in parallel
+func (r *redis) checkMasters(ctx context.Context) []api.NodeHealth {
+	ctx, cancel := healthCheckContext(ctx)
+	defer cancel()
+	clients, err := r.masters()
+	if err != nil {
+		return []api.NodeHealth{{Role: roleMaster, Err: wrapError(err)}}
+	}
+	return checkNodes(clients, func(addr string, client radix.Client) api.NodeHealth {
+		node := api.NodeHealth{Addr: addr, Role: roleMaster}
+		start := time.Now()
+		err := client.Do(ctx, radix.Cmd(nil, "PING"))
+		node.Latency = time.Since(start)
+		if err != nil {
+			node.Err = wrapError(err)
+			return node
+		}
+		if r.single != nil {
+			// single node client may be connected to replica; role name is received as bulk string
+			var role []interface{}
+			if client.Do(ctx, radix.Cmd(&role, "ROLE")) == nil && len(role) > 0 && fmt.Sprintf("%s", role[0]) == "slave" {
+				node.Role = roleReplica
+			}
+		}
+		return node
+	})
+}
+
+// checkSentinels checks sentinel quorum for the master with every known sentinel;
+// quorum is considered reached if any of sentinels confirms it
+func (r *redis) checkSentinels(ctx context.Context) ([]api.NodeHealth, error) {
+	ctx, cancel := healthCheckContext(ctx)
+	defer cancel()
+	addrs, err := r.sentinel.SentinelAddrs()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	sentinels := make(map[string]radix.Client, len(addrs))
+	for _, addr := range addrs {
+		sentinels[addr] = nil
+	}
+	nodes := checkNodes(sentinels, func(addr string, _ radix.Client) api.NodeHealth {
+		node := api.NodeHealth{Addr: addr, Role: roleSentinel}
+		start := time.Now()
+		conn, err := r.sentinelDialer.Dial(ctx, "tcp", addr)
+		if err == nil {
+			err = conn.Do(ctx, radix.Cmd(nil, "SENTINEL", "CKQUORUM", r.masterName))
+			_ = conn.Close()
+		}
+		node.Latency = time.Since(start)
+		node.Err = wrapError(err)
+		return node
+	})
+	for _, node := range nodes {
+		if node.Err == nil {
+			return nodes, nil
+		}
+	}
+	return nodes, ErrNoQuorum
+}
+
+// healthCheckContext applies defaultHealthCheckTimeout to context without deadline
+func healthCheckContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, defaultHealthCheckTimeout)
+}
+
+// checkNodes runs check for every node in parallel, results are sorted by address
+func checkNodes(clients map[string]radix.Client, check func(addr string, client radix.Client) api.NodeHealth) []api.NodeHealth {
+	nodes := make([]api.NodeHealth, 0, len(clients))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for addr, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			node := check(addr, client)
+			mutex.Lock()
+			defer mutex.Unlock()
+			nodes = append(nodes, node)
+		}()
+	}
+	wg.Wait()
+	slices.SortFunc(nodes, func(a, b api.NodeHealth) int {
+		return strings.Compare(a.Addr, b.Addr)
+	})
+	return nodes
+}
+func nodeError(node api.NodeHealth) error {
+	if node.Addr == "" {
+		return node.Err
+	}
+	return fmt.Errorf("%s %s: %w", node.Role, node.Addr, node.Err)
+}
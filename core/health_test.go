@@ -0,0 +1,161 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4/resp"
+	"github.com/mediocregopher/radix/v4/resp/resp3"
+	"github.com/stvp/tempredis"
+	"go.slink.ws/redisson/api"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	if err = r.Ping(context.Background()); err != nil {
+		t.Errorf("unexpected ping error: %v", err)
+	}
+	report := r.CheckHealth(context.Background())
+	if report.Err != nil || !report.Ready() || len(report.Nodes) != 1 {
+		t.Fatalf("unexpected health report %+v", report)
+	}
+	node := report.Nodes[0]
+	if node.Addr != fmt.Sprintf("%s:%d", testServerHost, testServerPort) || node.Role != "master" ||
+		node.Latency <= 0 || node.Err != nil {
+		t.Errorf("unexpected node health %+v", node)
+	}
+}
+func TestHealthUnavailable(t *testing.T) {
+	const port = testServerPort + 10
+	s, err := tempredis.Start(tempredis.Config{
+		"port": strconv.Itoa(port),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// single connection pool holds no connections created in background, so the outcome does not depend on timing
+	r, err := NewConfig().WithPoolSize(1).NewSingle(fmt.Sprintf("%s:%d", testServerHost, port))
+	if err != nil {
+		_ = s.Term()
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	_ = s.Term()
+
+	// connection of unavailable node fails and is discarded
+	if err = r.Ping(context.Background()); !errors.Is(err, ErrConnection) {
+		t.Errorf("expected '%v', received '%v'", ErrConnection, err)
+	}
+	// breaker state is reported without issuing commands
+	start := time.Now()
+	if r.Health().Circuit != api.CircuitClosed || time.Since(start) > 50*time.Millisecond {
+		t.Errorf("expected '%s' without waiting for node, received '%s' in %v", api.CircuitClosed, r.Health().Circuit, time.Since(start))
+	}
+	// then pool waits for connection to unavailable node until context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	report := r.CheckHealth(ctx)
+	if report.Ready() || len(report.Nodes) != 1 || !errors.Is(report.Nodes[0].Err, ErrTimeout) {
+		t.Errorf("expected '%v' of unavailable node, received %+v", ErrTimeout, report)
+	}
+}
+func TestHealthSentinel(t *testing.T) {
+	master := fmt.Sprintf("%s:%d", testServerHost, testServerPort)
+	for _, quorum := range []bool{true, false} {
+		sentinel := startStubSentinel(t, "TEST_MASTER", master, quorum)
+		r, err := NewConfig().NewSentinel("TEST_MASTER", sentinel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report := r.CheckHealth(context.Background())
+		_ = r.Close()
+		if len(report.Nodes) != 2 || report.Nodes[0].Addr != master || report.Nodes[0].Err != nil {
+			t.Fatalf("unexpected health report %+v", report)
+		}
+		node := report.Nodes[1]
+		if node.Addr != sentinel || node.Role != roleSentinel || (node.Err == nil) != quorum {
+			t.Errorf("unexpected sentinel health %+v", node)
+		}
+		if report.Ready() != quorum || errors.Is(report.Err, ErrNoQuorum) == quorum {
+			t.Errorf("expected quorum %v, received '%v'", quorum, report.Err)
+		}
+	}
+}
+
+// startStubSentinel starts sentinel replying to the commands of radix sentinel client and to SENTINEL CKQUORUM;
+// it monitors single master without replicas and other sentinels
+func startStubSentinel(t *testing.T, name, masterAddr string, quorum bool) string {
+	t.Helper()
+	l, err := net.Listen("tcp", net.JoinHostPort(testServerHost, "0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+	host, port, _ := net.SplitHostPort(masterAddr)
+	bulk := func(values ...string) string {
+		reply := fmt.Sprintf("*%d\r\n", len(values))
+		for _, value := range values {
+			reply += fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+		}
+		return reply
+	}
+	reply := func(args []string) string {
+		cmd := strings.ToUpper(strings.Join(args, " "))
+		switch {
+		case cmd == "PING":
+			return "+PONG\r\n"
+		case strings.HasPrefix(cmd, "SUBSCRIBE "):
+			return fmt.Sprintf("*3\r\n$9\r\nsubscribe\r\n$%d\r\n%s\r\n:1\r\n", len(args[1]), args[1])
+		case len(args) == 3 && args[2] != name:
+			return "-ERR No such master with that name\r\n"
+		case strings.HasPrefix(cmd, "SENTINEL SENTINELS "), strings.HasPrefix(cmd, "SENTINEL SLAVES "):
+			return "*0\r\n"
+		case strings.HasPrefix(cmd, "SENTINEL MASTER "):
+			return bulk("name", name, "ip", host, "port", port, "flags", "master")
+		case strings.HasPrefix(cmd, "SENTINEL CKQUORUM ") && quorum:
+			return "+OK 1 usable Sentinels. Quorum and failover authorization can be reached\r\n"
+		case strings.HasPrefix(cmd, "SENTINEL CKQUORUM "):
+			return "-NOQUORUM 1 usable Sentinels. Not enough available Sentinels to reach the majority\r\n"
+		}
+		return "-ERR unknown command\r\n"
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				br, opts := bufio.NewReader(conn), resp.NewOpts()
+				for {
+					var args []string
+					if err := resp3.Unmarshal(br, &args, opts); err != nil || len(args) == 0 {
+						return
+					}
+					if _, err := conn.Write([]byte(reply(args))); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
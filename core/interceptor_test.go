@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestInterceptor(t *testing.T) {
+	var mutex sync.Mutex
+	var commands []string
+	var order []string
+	errInjected := errors.New("injected failure")
+
+	r, err := NewConfig().
+		WithInterceptor(func(ctx context.Context, cmd api.Command, next api.CommandHandler) error {
+			mutex.Lock()
+			commands = append(commands, cmd.Name+" "+strings.Join(cmd.Keys, ","))
+			order = append(order, "first")
+			mutex.Unlock()
+			return next(ctx, cmd)
+		}).
+		WithInterceptor(func(ctx context.Context, cmd api.Command, next api.CommandHandler) error {
+			mutex.Lock()
+			order = append(order, "second")
+			mutex.Unlock()
+			if slices.Contains(cmd.Keys, "TEST_FAULT_KEY") {
+				return errInjected
+			}
+			return next(ctx, cmd)
+		}).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	_ = r.Set("TEST_KEY", "TEST_VALUE")
+	_, _ = r.Del("TEST_KEY")
+	_ = NewRMap("TEST_MAP", r).Set("key", "value")
+	_ = NewRList("TEST_LIST", r).LPush("value")
+	_ = NewRSet("TEST_SET", r).Add("value")
+	_, _ = NewRBitSet("TEST_BITSET", r).Set(1, 1)
+	_, _ = r.Del("TEST_MAP", "TEST_LIST", "TEST_SET", "TEST_BITSET")
+
+	expected := []string{
+		"SET TEST_KEY",
+		"DEL TEST_KEY",
+		"HSET TEST_MAP",
+		"LPUSH TEST_LIST",
+		"SADD TEST_SET",
+		"SETBIT TEST_BITSET",
+		"DEL TEST_MAP,TEST_LIST,TEST_SET,TEST_BITSET",
+	}
+	if fmt.Sprint(commands) != fmt.Sprint(expected) {
+		t.Errorf("expected '%v', received '%v'", expected, commands)
+	}
+	if len(order) != 2*len(expected) || order[0] != "first" || order[1] != "second" {
+		t.Errorf("expected interceptors to be called in order, received '%v'", order)
+	}
+
+	commands = nil
+	m, err := NewRCacheMap("TEST_CACHE_MAP", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = m.Set("key", "value")
+	m.Destroy()
+	_, _ = r.Del("TEST_CACHE_MAP")
+	mutex.Lock()
+	if !slices.Contains(commands, "HKEYS TEST_CACHE_MAP") || !slices.Contains(commands, "HSET TEST_CACHE_MAP") {
+		t.Errorf("expected RCacheMap commands to be intercepted, received '%v'", commands)
+	}
+	mutex.Unlock()
+
+	commands = nil
+	_ = r.Do(radix.FlatCmd(nil, "MSET", "TEST_KEY", 1, "TEST_KEY2", 2))
+	_ = r.Do(radix.Cmd(nil, "DEL", "TEST_KEY", "TEST_KEY2"))
+	expected = []string{"MSET TEST_KEY,TEST_KEY2", "DEL TEST_KEY,TEST_KEY2"}
+	if fmt.Sprint(commands) != fmt.Sprint(expected) {
+		t.Errorf("expected '%v', received '%v'", expected, commands)
+	}
+
+	err = r.Set("TEST_FAULT_KEY", "TEST_VALUE")
+	if !errors.Is(err, errInjected) {
+		t.Errorf("expected '%v', received '%v'", errInjected, err)
+	}
+	if r.Exists("TEST_FAULT_KEY") {
+		t.Errorf("expected command not to be issued")
+	}
+}
+func TestInterceptorRewrite(t *testing.T) {
+	type ctxKey struct{}
+	var values []any
+
+	r, err := NewConfig().
+		WithInterceptor(func(ctx context.Context, cmd api.Command, next api.CommandHandler) error {
+			if cmd.Name == "GET" {
+				// redirect reads to another key
+				cmd.Action = radix.Cmd(nil, "SET", "TEST_REWRITTEN_KEY", "TEST_VALUE")
+			}
+			return next(context.WithValue(ctx, ctxKey{}, "value"), cmd)
+		}).
+		WithInterceptor(func(ctx context.Context, cmd api.Command, next api.CommandHandler) error {
+			values = append(values, ctx.Value(ctxKey{}))
+			return next(ctx, cmd)
+		}).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	_, _ = r.Get("TEST_KEY")
+	if !r.Exists("TEST_REWRITTEN_KEY") {
+		t.Errorf("expected rewritten command to be issued")
+	}
+	_, _ = r.Del("TEST_REWRITTEN_KEY")
+	if len(values) == 0 || values[0] != "value" {
+		t.Errorf("expected context to be passed to the next interceptor, received '%v'", values)
+	}
+}
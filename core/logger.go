@@ -0,0 +1,196 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"go.slink.ws/redisson/api"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// region - slog logger
+
+// slogLevelNotice is between slog.LevelInfo and slog.LevelWarn as slog has no notice level
+const slogLevelNotice = slog.LevelInfo + 2
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates logger writing to slog logger; nil logger stands for slog.Default() at the time of logging
+func NewSlogLogger(logger *slog.Logger) api.StructuredLogger {
+	return &slogLogger{logger: logger}
+}
+
+func (l *slogLogger) Debug(message string, args ...interface{}) {
+	l.printf(slog.LevelDebug, message, args...)
+}
+func (l *slogLogger) Notice(message string, args ...interface{}) {
+	l.printf(slogLevelNotice, message, args...)
+}
+func (l *slogLogger) Info(message string, args ...interface{}) {
+	l.printf(slog.LevelInfo, message, args...)
+}
+func (l *slogLogger) Warning(message string, args ...interface{}) {
+	l.printf(slog.LevelWarn, message, args...)
+}
+func (l *slogLogger) Error(message string, args ...interface{}) {
+	l.printf(slog.LevelError, message, args...)
+}
+func (l *slogLogger) Log(level api.LogLevel, message string, fields ...api.LogField) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		attrs = append(attrs, slog.Any(field.Key, field.Value))
+	}
+	l.slog().LogAttrs(context.Background(), slogLevel(level), message, attrs...)
+}
+func (l *slogLogger) printf(level slog.Level, message string, args ...interface{}) {
+	logger := l.slog()
+	if !logger.Enabled(context.Background(), level) {
+		return
+	}
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	logger.Log(context.Background(), level, message)
+}
+func (l *slogLogger) slog() *slog.Logger {
+	if l.logger == nil {
+		return slog.Default()
+	}
+	return l.logger
+}
+
+// endregion
+// region - slog handler
+
+type loggerHandler struct {
+	logger api.Logger
+	fields []api.LogField
+	group  string
+}
+
+// NewSlogHandler creates slog handler writing to logger; attributes are passed as fields to api.StructuredLogger
+// and appended to message as 'key=value' for other loggers; nil logger stands for handler of slog.Default()
+// at the time of creation, so the result could be set as default one without looping
+func NewSlogHandler(logger api.Logger) slog.Handler {
+	if logger == nil {
+		return slog.Default().Handler()
+	}
+	return &loggerHandler{logger: logger}
+}
+
+func (h *loggerHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+func (h *loggerHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := slices.Clone(h.fields)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendLogFields(fields, h.group, attr)
+		return true
+	})
+	logStructured(h.logger, logLevel(record.Level), record.Message, fields...)
+	return nil
+}
+func (h *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := slices.Clone(h.fields)
+	for _, attr := range attrs {
+		fields = appendLogFields(fields, h.group, attr)
+	}
+	return &loggerHandler{logger: h.logger, fields: fields, group: h.group}
+}
+func (h *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &loggerHandler{logger: h.logger, fields: h.fields, group: h.group + name + "."}
+}
+
+// appendLogFields flattens attribute, keys of group members are prefixed with group name
+func appendLogFields(fields []api.LogField, group string, attr slog.Attr) []api.LogField {
+	value := attr.Value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		if attr.Key == "" {
+			return fields
+		}
+		return append(fields, api.LogField{Key: group + attr.Key, Value: value.Any()})
+	}
+	if attr.Key != "" {
+		group += attr.Key + "."
+	}
+	for _, member := range value.Group() {
+		fields = appendLogFields(fields, group, member)
+	}
+	return fields
+}
+
+// endregion
+// region - helpers
+
+// logStructured logs message with fields to structured loggers, others receive fields formatted within message
+func logStructured(logger api.Logger, level api.LogLevel, message string, fields ...api.LogField) {
+	if sl, ok := logger.(api.StructuredLogger); ok {
+		sl.Log(level, message, fields...)
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(message)
+	for _, field := range fields {
+		_, _ = fmt.Fprintf(&sb, " %s=%v", field.Key, field.Value)
+	}
+	switch level {
+	case api.LevelDebug:
+		logger.Debug("%s", sb.String())
+	case api.LevelInfo:
+		logger.Info("%s", sb.String())
+	case api.LevelNotice:
+		logger.Notice("%s", sb.String())
+	case api.LevelWarning:
+		logger.Warning("%s", sb.String())
+	default:
+		logger.Error("%s", sb.String())
+	}
+}
+func keyField(key string) api.LogField {
+	return api.LogField{Key: "key", Value: key}
+}
+func fieldField(field string) api.LogField {
+	return api.LogField{Key: "field", Value: field}
+}
+func commandField(command string) api.LogField {
+	return api.LogField{Key: "command", Value: command}
+}
+func errorField(err error) api.LogField {
+	return api.LogField{Key: "error", Value: err}
+}
+func slogLevel(level api.LogLevel) slog.Level {
+	switch level {
+	case api.LevelDebug:
+		return slog.LevelDebug
+	case api.LevelInfo:
+		return slog.LevelInfo
+	case api.LevelNotice:
+		return slogLevelNotice
+	case api.LevelWarning:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+func logLevel(level slog.Level) api.LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return api.LevelDebug
+	case level < slogLevelNotice:
+		return api.LevelInfo
+	case level < slog.LevelWarn:
+		return api.LevelNotice
+	case level < slog.LevelError:
+		return api.LevelWarning
+	default:
+		return api.LevelError
+	}
+}
+
+// endregion
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4/trace"
+	"go.slink.ws/redisson/api"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testLogEntry struct {
+	level   api.LogLevel
+	message string
+	fields  map[string]any
+}
+
+// testStructuredLogger records structured messages
+type testStructuredLogger struct {
+	testLogger
+	mutex   sync.Mutex
+	entries []testLogEntry
+}
+
+func (l *testStructuredLogger) Log(level api.LogLevel, message string, fields ...api.LogField) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	entry := testLogEntry{level: level, message: message, fields: map[string]any{}}
+	for _, field := range fields {
+		entry.fields[field.Key] = field.Value
+	}
+	l.entries = append(l.entries, entry)
+}
+func (l *testStructuredLogger) find(message string) (testLogEntry, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, entry := range l.entries {
+		if entry.message == message {
+			return entry, true
+		}
+	}
+	return testLogEntry{}, false
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	logger.Debug("debug %d", 1)
+	logger.Notice("notice 100%")
+	logger.Log(api.LevelWarning, "sync error", api.LogField{Key: "key", Value: "map"},
+		api.LogField{Key: "error", Value: errors.New("failure")})
+
+	text := buf.String()
+	for _, expected := range []string{
+		`level=DEBUG msg="debug 1"`,
+		`level=INFO+2 msg="notice 100%"`,
+		`level=WARN msg="sync error" key=map error=failure`,
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected '%s' in:\n%s", expected, text)
+		}
+	}
+}
+func TestSlogHandler(t *testing.T) {
+	plain := &testLogger{}
+	slog.New(NewSlogHandler(plain)).
+		With("key", "map").
+		WithGroup("cmd").
+		Warn("sync error", "name", "HGET", slog.Group("reply", "size", 2))
+	expected := []string{"warning: sync error key=map cmd.name=HGET cmd.reply.size=2"}
+	if fmt.Sprint(plain.Messages()) != fmt.Sprint(expected) {
+		t.Errorf("expected '%v', received '%v'", expected, plain.Messages())
+	}
+
+	structured := &testStructuredLogger{}
+	slog.New(NewSlogHandler(structured)).Error("failure", "key", "map", "count", 3)
+	entry, ok := structured.find("failure")
+	if !ok || entry.level != api.LevelError || entry.fields["key"] != "map" || entry.fields["count"] != int64(3) {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(defaultLogger)
+	slog.SetDefault(slog.New(NewSlogHandler(nil)))
+	slog.Info("default", "key", "map")
+	if !strings.Contains(buf.String(), `level=INFO msg=default key=map`) {
+		t.Errorf("expected message written to default handler, received '%s'", buf.String())
+	}
+}
+func TestClientStructuredLogging(t *testing.T) {
+	logger := &testStructuredLogger{}
+	r, err := NewConfig().
+		WithLogger(logger).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	err = r.Set("TEST_LOG_STRING", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_, _ = r.Del("TEST_LOG_STRING")
+	}()
+	_, _ = NewRMap("TEST_LOG_STRING", r).Get("field")
+	entry, ok := logger.find("RMap get error")
+	if !ok || entry.level != api.LevelWarning || entry.fields["key"] != "TEST_LOG_STRING" ||
+		entry.fields["command"] != "HGET" || !errors.Is(entry.fields["error"].(error), ErrWrongType) {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	r.(*redis).pubSubConfig().Trace.InternalError(trace.PersistentPubSubInternalError{Err: errors.New("failure")})
+	if entry, ok := logger.find("pub/sub connection error, reconnecting"); !ok || entry.fields["error"] == nil {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+}
+func TestDefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	r, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	r.Warning("warning %s", "message")
+	if !strings.Contains(buf.String(), `level=WARN msg="warning message"`) {
+		t.Errorf("expected message logged with slog.Default(), got '%s'", buf.String())
+	}
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// region - metrics
+
+const metricsNamespace = "redisson"
+
+// defaultMetricsBuckets are latency histogram bounds in seconds
+var defaultMetricsBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// NewMetrics creates in-process metrics registry which could be rendered in Prometheus text exposition format
+func NewMetrics() *metrics {
+	return &metrics{
+		buckets:   defaultMetricsBuckets,
+		commands:  make(map[string]*metricSeries),
+		pools:     make(map[string]poolMetrics),
+		cacheMaps: make(map[string]*metricSeries),
+	}
+}
+
+type metrics struct {
+	mutex            sync.Mutex
+	buckets          []float64
+	commands         map[string]*metricSeries
+	pools            map[string]poolMetrics
+	pubSubReconnects uint64
+	cacheMaps        map[string]*metricSeries
+}
+
+// metricSeries counts operations, their errors and durations
+type metricSeries struct {
+	total   uint64
+	errors  uint64
+	buckets []uint64
+	sum     float64
+}
+
+type poolMetrics struct {
+	size, open, inUse int
+}
+
+// WithBuckets sets latency histogram bounds in seconds; it resets collected command and RCacheMap metrics
+func (m *metrics) WithBuckets(buckets ...float64) *metrics {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.buckets = slices.Sorted(slices.Values(buckets))
+	m.commands = make(map[string]*metricSeries)
+	m.cacheMaps = make(map[string]*metricSeries)
+	return m
+}
+
+func (m *metrics) CommandDone(command string, duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.observe(m.commands, command, duration, err)
+}
+func (m *metrics) PoolChanged(addr string, size, open, inUse int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.pools[addr] = poolMetrics{size: size, open: open, inUse: inUse}
+}
+func (m *metrics) PubSubReconnected() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.pubSubReconnects++
+}
+func (m *metrics) CacheMapSynced(key string, duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.observe(m.cacheMaps, key, duration, err)
+}
+
+func (m *metrics) observe(series map[string]*metricSeries, name string, duration time.Duration, err error) {
+	s, ok := series[name]
+	if !ok {
+		s = &metricSeries{buckets: make([]uint64, len(m.buckets))}
+		series[name] = s
+	}
+	s.total++
+	if err != nil {
+		s.errors++
+	}
+	seconds := duration.Seconds()
+	s.sum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+// endregion
+// region - prometheus
+
+// WritePrometheus renders metrics in Prometheus text exposition format
+func (m *metrics) WritePrometheus(w io.Writer) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	bw := bufio.NewWriter(w)
+
+	m.writeSeries(bw, "command", "command", m.commands, "redis commands")
+	writeMetricHeader(bw, "pool_size", "gauge", "Configured size of node connection pool.")
+	for _, addr := range slices.Sorted(maps.Keys(m.pools)) {
+		writeMetric(bw, "pool_size", "addr", addr, float64(m.pools[addr].size))
+	}
+	writeMetricHeader(bw, "pool_connections", "gauge", "Open connections of node connection pool.")
+	for _, addr := range slices.Sorted(maps.Keys(m.pools)) {
+		writeMetric(bw, "pool_connections", "addr", addr, float64(m.pools[addr].open))
+	}
+	writeMetricHeader(bw, "pool_connections_in_use", "gauge", "Connections of node connection pool with commands in flight.")
+	for _, addr := range slices.Sorted(maps.Keys(m.pools)) {
+		writeMetric(bw, "pool_connections_in_use", "addr", addr, float64(m.pools[addr].inUse))
+	}
+	writeMetricHeader(bw, "pubsub_reconnects_total", "counter", "Re-established pub/sub connections.")
+	writeMetric(bw, "pubsub_reconnects_total", "", "", float64(m.pubSubReconnects))
+	m.writeSeries(bw, "cachemap_sync", "key", m.cacheMaps, "RCacheMap synchronizations")
+
+	return bw.Flush()
+}
+
+// ServeHTTP serves metrics in Prometheus text exposition format
+func (m *metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
+
+// writeSeries renders <name>s_total and <name>_errors_total counters and <name>_duration_seconds histogram
+func (m *metrics) writeSeries(w io.Writer, name, label string, series map[string]*metricSeries, help string) {
+	names := slices.Sorted(maps.Keys(series))
+	writeMetricHeader(w, name+"s_total", "counter", "Total "+help+".")
+	for _, n := range names {
+		writeMetric(w, name+"s_total", label, n, float64(series[n].total))
+	}
+	writeMetricHeader(w, name+"_errors_total", "counter", "Failed "+help+".")
+	for _, n := range names {
+		writeMetric(w, name+"_errors_total", label, n, float64(series[n].errors))
+	}
+	histogram := name + "_duration_seconds"
+	writeMetricHeader(w, histogram, "histogram", "Duration of "+help+" in seconds.")
+	for _, n := range names {
+		s := series[n]
+		labels := fmt.Sprintf(`%s="%s"`, label, escapeMetricLabel(n))
+		for i, bound := range m.buckets {
+			_, _ = fmt.Fprintf(w, "%s_%s_bucket{%s,le=\"%s\"} %d\n", metricsNamespace, histogram, labels,
+				strconv.FormatFloat(bound, 'g', -1, 64), s.buckets[i])
+		}
+		_, _ = fmt.Fprintf(w, "%s_%s_bucket{%s,le=\"+Inf\"} %d\n", metricsNamespace, histogram, labels, s.total)
+		_, _ = fmt.Fprintf(w, "%s_%s_sum{%s} %s\n", metricsNamespace, histogram, labels, strconv.FormatFloat(s.sum, 'g', -1, 64))
+		_, _ = fmt.Fprintf(w, "%s_%s_count{%s} %d\n", metricsNamespace, histogram, labels, s.total)
+	}
+}
+
+func writeMetricHeader(w io.Writer, name, kind, help string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s_%s %s\n# TYPE %s_%s %s\n", metricsNamespace, name, help, metricsNamespace, name, kind)
+}
+func writeMetric(w io.Writer, name, label, value string, v float64) {
+	if label == "" {
+		_, _ = fmt.Fprintf(w, "%s_%s %s\n", metricsNamespace, name, strconv.FormatFloat(v, 'g', -1, 64))
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s_%s{%s=\"%s\"} %s\n", metricsNamespace, name, label, escapeMetricLabel(value),
+		strconv.FormatFloat(v, 'g', -1, 64))
+}
+func escapeMetricLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// endregion
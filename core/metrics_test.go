@@ -0,0 +1,112 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4/trace"
+	"go.slink.ws/redisson/api"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics().WithBuckets(0.1, 0.01)
+	m.CommandDone("GET", 5*time.Millisecond, nil)
+	m.CommandDone("GET", 50*time.Millisecond, errors.New("failure"))
+	m.CommandDone("SET", time.Second, nil)
+	m.PoolChanged("127.0.0.1:6379", 5, 3, 1)
+	m.PubSubReconnected()
+	m.CacheMapSynced("cache\"map", time.Millisecond, nil)
+
+	var buf bytes.Buffer
+	err := m.WritePrometheus(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	for _, line := range []string{
+		"# TYPE redisson_commands_total counter",
+		`redisson_commands_total{command="GET"} 2`,
+		`redisson_commands_total{command="SET"} 1`,
+		`redisson_command_errors_total{command="GET"} 1`,
+		`redisson_command_errors_total{command="SET"} 0`,
+		"# TYPE redisson_command_duration_seconds histogram",
+		`redisson_command_duration_seconds_bucket{command="GET",le="0.01"} 1`,
+		`redisson_command_duration_seconds_bucket{command="GET",le="0.1"} 2`,
+		`redisson_command_duration_seconds_bucket{command="GET",le="+Inf"} 2`,
+		`redisson_command_duration_seconds_bucket{command="SET",le="0.1"} 0`,
+		`redisson_command_duration_seconds_sum{command="SET"} 1`,
+		`redisson_command_duration_seconds_count{command="SET"} 1`,
+		`redisson_pool_size{addr="127.0.0.1:6379"} 5`,
+		`redisson_pool_connections{addr="127.0.0.1:6379"} 3`,
+		`redisson_pool_connections_in_use{addr="127.0.0.1:6379"} 1`,
+		"redisson_pubsub_reconnects_total 1",
+		`redisson_cachemap_syncs_total{key="cache\"map"} 1`,
+		`redisson_cachemap_sync_duration_seconds_count{key="cache\"map"} 1`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("expected '%s' in:\n%s", line, text)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Body.String() != text {
+		t.Errorf("expected served metrics to match rendered ones")
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("unexpected content type '%s'", rec.Header().Get("Content-Type"))
+	}
+}
+func TestClientMetrics(t *testing.T) {
+	m := NewMetrics()
+	addr := fmt.Sprintf("%s:%d", testServerHost, testServerPort)
+	r, err := NewConfig().
+		WithPoolSize(3).
+		WithMetrics(m).
+		NewSingle(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	_ = r.Set("TEST_KEY", "TEST_VALUE")
+	_, _ = r.Get("TEST_KEY")
+	_, _ = r.Get("TEST_KEY")
+	_, _ = r.Del("TEST_KEY")
+	_ = NewRMap("TEST_MAP", r).Set("key", "value")
+
+	cm, err := NewRCacheMap("TEST_CACHE_MAP", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cm.Destroy()
+	_, _ = r.Del("TEST_MAP")
+
+	r.(*redis).pubSubConfig().Trace.InternalError(trace.PersistentPubSubInternalError{Err: ErrConnection})
+
+	var buf bytes.Buffer
+	_ = m.WritePrometheus(&buf)
+	text := buf.String()
+	for _, line := range []string{
+		`redisson_commands_total{command="SET"} 1`,
+		`redisson_commands_total{command="GET"} 2`,
+		`redisson_commands_total{command="HSET"} 1`,
+		`redisson_command_errors_total{command="GET"} 0`,
+		fmt.Sprintf(`redisson_pool_size{addr="%s"} 3`, addr),
+		fmt.Sprintf(`redisson_pool_connections{addr="%s"} 3`, addr),
+		fmt.Sprintf(`redisson_pool_connections_in_use{addr="%s"} 0`, addr),
+		"redisson_pubsub_reconnects_total 1",
+		`redisson_cachemap_syncs_total{key="TEST_CACHE_MAP"} 1`,
+		`redisson_cachemap_sync_errors_total{key="TEST_CACHE_MAP"} 0`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("expected '%s' in:\n%s", line, text)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"go.slink.ws/redisson/api"
+	"strings"
+)
+
+// Namespace returns view of the client prefixing keys of objects and common functions;
+// namespaces are nested, so prefix is appended to the prefix of the client
+func (r *redis) Namespace(prefix string) api.Redis {
+	c := *r
+	c.prefix = r.prefix + prefix
+	return &c
+}
+func (r *redis) keyPrefix() string {
+	return r.prefix
+}
+func (r *redis) key(key string) string {
+	return r.prefix + key
+}
+func (r *redis) keys(keys []string) []string {
+	if r.prefix == "" {
+		return keys
+	}
+	result := make([]string, len(keys))
+	for i, key := range keys {
+		result[i] = r.prefix + key
+	}
+	return result
+}
+
+// stripKeys removes prefix from keys returned by redis
+func (r *redis) stripKeys(keys []string) []string {
+	if r.prefix == "" {
+		return keys
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, r.prefix)
+	}
+	return keys
+}
+
+// namespaced clients prefix keys of objects created with them
+type namespaced interface {
+	keyPrefix() string
+}
+
+func namespacedKey(client api.Redis, key string) string {
+	if n, ok := client.(namespaced); ok {
+		return n.keyPrefix() + key
+	}
+	return key
+}
+
+// escapeGlob escapes glob-style pattern special characters, so string is matched literally
+func escapeGlob(s string) string {
+	if !strings.ContainsAny(s, `*?[]\`) {
+		return s
+	}
+	var sb strings.Builder
+	for _, c := range s {
+		if strings.ContainsRune(`*?[]\`, c) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
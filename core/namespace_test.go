@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+	"go.slink.ws/redisson/api"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestKeyPrefix(t *testing.T) {
+	raw, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(raw)
+	r, err := NewConfig().
+		WithKeyPrefix("TEST_NS:").
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	_ = raw.Set("TEST_NS_OUTSIDE", "value")
+	_ = r.Set("key", "value")
+	_, _ = r.Incr("counter")
+	inner := r.Namespace("inner:")
+	_ = inner.Set("key", "inner value")
+	_ = NewRList("list", r).RPush("item")
+
+	for key, expected := range map[string]string{
+		"TEST_NS:key":       "value",
+		"TEST_NS:counter":   "1",
+		"TEST_NS:inner:key": "inner value",
+	} {
+		if v, err := raw.Get(key); err != nil || v.String() != expected {
+			t.Errorf("expected %s='%s', received '%v' (%v)", key, expected, v, err)
+		}
+	}
+	if v, err := inner.Get("key"); err != nil || v.String() != "inner value" {
+		t.Errorf("expected namespaced get, received '%v' (%v)", v, err)
+	}
+	if raw.Type("TEST_NS:list") != "list" || r.Type("list") != "list" {
+		t.Errorf("expected list to be created under prefix")
+	}
+
+	keys := r.Keys("")
+	slices.Sort(keys)
+	if fmt.Sprint(keys) != "[counter inner:key key list]" {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	if keys = inner.Keys("k*"); fmt.Sprint(keys) != "[key]" {
+		t.Errorf("unexpected inner keys %v", keys)
+	}
+	if !r.Exists("key", "list") || r.Exists("TEST_NS_OUTSIDE") {
+		t.Errorf("unexpected exists result")
+	}
+	if n, err := r.Del("key", "counter", "list", "inner:key"); n != 4 || err != nil {
+		t.Errorf("expected 4 keys deleted, received %d (%v)", n, err)
+	}
+	_, _ = raw.Del("TEST_NS_OUTSIDE")
+}
+func TestKeyPrefixCacheMap(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	err = r.EnableKeyEventNotifications()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// glob characters of prefix are escaped in notification pattern
+	ns := r.Namespace("TEST_NS*[1]:")
+
+	m, err := NewRCacheMap("cache", ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Destroy()
+
+	// change made with another client is delivered via notification of the prefixed key
+	err = NewRMap("TEST_NS*[1]:cache", r).Set("key1", "value1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	value, ok := m.Get("key1")
+	for !ok && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+		value, ok = m.Get("key1")
+	}
+	if !ok || value.String() != "value1" {
+		t.Errorf("expected '%s', but received '%s'", "value1", value.String())
+	}
+	if keys := ns.Keys("*"); fmt.Sprint(keys) != "[cache]" {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	_, _ = ns.Del("cache")
+}
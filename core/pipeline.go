@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"github.com/mediocregopher/radix/v4/resp"
+	"github.com/mediocregopher/radix/v4/resp/resp3"
+)
+
+const pipelineCommand = "PIPELINE"
+const transactionCommand = "MULTI"
+
+// multiAction issues several commands on the same connection in a single round-trip,
+// either pipelined or within MULTI / EXEC transaction
+type multiAction struct {
+	name string
+	cmds []radix.Action
+}
+
+func newPipelineAction(cmds []radix.Action) *multiAction {
+	return &multiAction{name: pipelineCommand, cmds: cmds}
+}
+func newTransactionAction(cmds []radix.Action) *multiAction {
+	return &multiAction{name: transactionCommand, cmds: cmds}
+}
+
+func (a *multiAction) Properties() radix.ActionProperties {
+	var keys []string
+	for _, cmd := range a.cmds {
+		keys = append(keys, cmd.Properties().Keys...)
+	}
+	return radix.ActionProperties{
+		Keys: keys,
+		// other commands must not be interleaved with transaction on the same connection
+		CanShareConn: a.name == pipelineCommand,
+	}
+}
+func (a *multiAction) Perform(ctx context.Context, conn radix.Conn) error {
+	p := radix.NewPipeline()
+	if a.name == pipelineCommand {
+		for _, cmd := range a.cmds {
+			p.Append(cmd)
+		}
+		return p.Perform(ctx, conn)
+	}
+	p.Append(radix.Cmd(nil, "MULTI"))
+	for _, cmd := range a.cmds {
+		m, ok := cmd.(resp.Marshaler)
+		if !ok {
+			return fmt.Errorf("action %T could not be issued within transaction", cmd)
+		}
+		p.Append(queuedAction{m})
+	}
+	p.Append(radix.Cmd(execReply(a.cmds), "EXEC"))
+	return p.Perform(ctx, conn)
+}
+
+// queuedAction sends command within transaction; its reply is received with EXEC
+type queuedAction struct {
+	cmd resp.Marshaler
+}
+
+func (a queuedAction) Properties() radix.ActionProperties {
+	return radix.ActionProperties{CanPipeline: true, CanShareConn: true}
+}
+func (a queuedAction) Perform(ctx context.Context, conn radix.Conn) error {
+	var status string
+	return conn.EncodeDecode(ctx, a.cmd, &status)
+}
+
+// execReply passes EXEC reply elements to the commands of transaction
+type execReply []radix.Action
+
+func (e execReply) UnmarshalRESP(br resp.BufferedReader, o *resp.Opts) error {
+	var head resp3.ArrayHeader
+	if err := head.UnmarshalRESP(br, o); err != nil {
+		return err
+	}
+	if head.NumElems < 0 {
+		return resp.ErrConnUsable{Err: ErrTransactionAborted}
+	}
+	var replyErr error
+	for i := 0; i < head.NumElems; i++ {
+		var err error
+		if u, ok := e.element(i).(resp.Unmarshaler); ok {
+			err = u.UnmarshalRESP(br, o)
+		} else {
+			err = resp3.Unmarshal(br, nil, o)
+		}
+		if err != nil && !errors.As(err, new(resp.ErrConnUsable)) {
+			return err
+		}
+		if replyErr == nil {
+			replyErr = err
+		}
+	}
+	return replyErr
+}
+func (e execReply) element(i int) radix.Action {
+	if i < len(e) {
+		return e[i]
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"github.com/mediocregopher/radix/v4"
 	"go.slink.ws/redisson/api"
@@ -15,7 +16,7 @@ type rbitset struct {
 func NewRBitSet(key string, client api.Redis) api.RBitSet {
 	return &rbitset{
 		client: client,
-		key:    key,
+		key:    namespacedKey(client, key),
 	}
 }
 
@@ -30,10 +31,14 @@ func (bs *rbitset) Get(idx uint32) (bool, error) {
 	return result > 0, err
 }
 func (bs *rbitset) BitCount() int {
-	var result int
-	_ = bs.client.Do(radix.Cmd(&result, "BITCOUNT", bs.key))
+	result, _ := bs.BitCountE()
 	return result
 }
+func (bs *rbitset) BitCountE() (int, error) {
+	var result int
+	err := bs.client.Do(radix.Cmd(&result, "BITCOUNT", bs.key))
+	return result, err
+}
 func (bs *rbitset) BitCountRange(start, end int, unit string) (int, error) {
 	if start > end && end >= 0 {
 		v := end
@@ -53,3 +58,9 @@ func (bs *rbitset) BitCountRange(start, end int, unit string) (int, error) {
 	err := bs.client.Do(radix.Cmd(&result, "BITCOUNT", bs.client.AnyArgs(bs.key, start, end, unit)...))
 	return result, err
 }
+func (bs *rbitset) WithContext(ctx context.Context) api.RBitSet {
+	return &rbitset{
+		client: bs.client.WithContext(ctx),
+		key:    bs.key,
+	}
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+)
+
+func NewRBucket(key string, client api.Redis) api.RBucket {
+	return NewRBucketWithCodec(key, client, client.Codec())
+}
+func NewRBucketWithCodec(key string, client api.Redis, codec api.Codec) api.RBucket {
+	return &rbucket{
+		client: client,
+		key:    namespacedKey(client, key),
+		codec:  codecOrDefault(codec),
+	}
+}
+
+type rbucket struct {
+	client api.Redis
+	key    string
+	codec  api.Codec
+}
+
+func (b *rbucket) Set(value any) error {
+	args, err := encodeArgs(b.codec, b.key, value)
+	if err != nil {
+		return err
+	}
+	return b.client.Do(radix.Cmd(nil, "SET", args...))
+}
+func (b *rbucket) WithContext(ctx context.Context) api.RBucket {
+	return &rbucket{
+		client: b.client.WithContext(ctx),
+		key:    b.key,
+		codec:  b.codec,
+	}
+}
+func (b *rbucket) Get() (api.Value, error) {
+	mb := radix.Maybe{Rcv: new(string)}
+	err := b.client.Do(radix.Cmd(&mb, "GET", b.key))
+	if err == nil && mb.Null {
+		err = ErrNotFound
+	}
+	return newMaybeValue(&mb, b.codec), err
+}
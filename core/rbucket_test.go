@@ -0,0 +1,78 @@
+package core
+
+import (
+	"go.slink.ws/redisson/api"
+	"testing"
+)
+
+func TestRBucket(t *testing.T) {
+
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	b := NewRBucket("TEST_BUCKET", r)
+
+	err = b.Set(42)
+	if err != nil {
+		t.Error(err)
+	}
+	v, err := b.Get()
+	if err != nil {
+		t.Error(err)
+	}
+	var i int
+	err = v.Decode(&i)
+	if err != nil {
+		t.Error(err)
+	}
+	if i != 42 {
+		t.Errorf("expected '%d', received '%d'", 42, i)
+	}
+
+	_, _ = r.Del("TEST_BUCKET")
+
+}
+func TestRBucketCodec(t *testing.T) {
+
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	for _, codec := range []api.Codec{NewJsonCodec(), NewGobCodec()} {
+		b := NewRBucketWithCodec("TEST_BUCKET", r, codec)
+
+		value := testCodecStruct{
+			Name:  "test",
+			Count: 3,
+			Tags:  []string{"a", "b"},
+		}
+		err = b.Set(value)
+		if err != nil {
+			t.Error(err)
+		}
+		v, err := b.Get()
+		if err != nil {
+			t.Error(err)
+		}
+		var result testCodecStruct
+		err = v.Decode(&result)
+		if err != nil {
+			t.Error(err)
+		}
+		if result.Name != value.Name || result.Count != value.Count || len(result.Tags) != len(value.Tags) {
+			t.Errorf("expected '%v', received '%v'", value, result)
+		}
+	}
+
+	_, _ = r.Del("TEST_BUCKET")
+
+}
@@ -5,19 +5,38 @@ import (
 	"errors"
 	"fmt"
 	"github.com/mediocregopher/radix/v4"
+	"github.com/mediocregopher/radix/v4/trace"
 	"go.slink.ws/redisson/api"
+	"reflect"
+	"sync/atomic"
 	"time"
 )
 
 const defaultKeyEventNotificationTypes = "KEAn"
 
-var ErrRedisClientNotInitialized = errors.New("redis client is not initialized")
-
 type redis struct {
 	single   radix.Client
 	sentinel *radix.Sentinel
 	cluster  *radix.Cluster
 	logger   api.Logger
+	codec    api.Codec
+	ctx      context.Context
+	dialer   radix.Dialer
+
+	// sentinel master name and dialer used for health checks
+	masterName     string
+	sentinelDialer radix.Dialer
+
+	readTimeout  time.Duration
+	retryPolicy  api.RetryPolicy
+	breaker      *circuitBreaker
+	interceptors []api.Interceptor
+	metrics      api.Metrics
+	tracer       api.Tracer
+	slowLog      *slowLog
+	prefix       string
+	events       *eventBus
+	conns        *connTracker
 }
 
 // region - redis
@@ -35,9 +54,33 @@ func (r *redis) Close() error {
 	} else {
 		err = ErrRedisClientNotInitialized
 	}
+	r.events.close()
 	return err
 }
 
+func (r *redis) WithContext(ctx context.Context) api.Redis {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+func (r *redis) Context() context.Context {
+	return r.defaultContext()
+}
+func (r *redis) SubscribeEvents(handler func(api.Event)) func() {
+	return r.events.subscribe(handler)
+}
+func (r *redis) Stats() api.Stats {
+	stats := r.conns.stats()
+	stats.DroppedEvents = r.events.dropped.Load()
+	return stats
+}
+func (r *redis) SlowCommands() []api.SlowCommand {
+	if r.slowLog == nil {
+		return nil
+	}
+	return r.slowLog.commands()
+}
+
 // endregion
 // region - common
 
@@ -52,58 +95,80 @@ func (r *redis) DisableKeyEventNotifications() error {
 }
 
 func (r *redis) Del(keys ...string) (int, error) {
-	var amount int
-	var err = r.Do(radix.Cmd(&amount, "DEL", keys...))
-	return amount, err
+	return r.countKeys("DEL", r.keys(keys))
 }
 func (r *redis) Expire(key string, ttl time.Duration) (int, error) {
 	var amount int
-	var err = r.Do(radix.Cmd(&amount, "EXPIRE", key, fmt.Sprintf("%0.f", ttl.Seconds())))
+	var err = r.Do(radix.Cmd(&amount, "EXPIRE", r.key(key), fmt.Sprintf("%0.f", ttl.Seconds())))
 	return amount, err
 }
 func (r *redis) Exists(keys ...string) bool {
-	var amount int
-	_ = r.Do(radix.Cmd(&amount, "EXISTS", keys...))
-	return amount == len(keys) && len(keys) > 0
+	exists, _ := r.ExistsE(keys...)
+	return exists
+}
+func (r *redis) ExistsE(keys ...string) (bool, error) {
+	amount, err := r.countKeys("EXISTS", r.keys(keys))
+	return amount == len(keys) && len(keys) > 0, err
 }
 func (r *redis) Keys(filter string) []string {
+	keys, _ := r.KeysE(filter)
+	return keys
+}
+func (r *redis) KeysE(filter string) ([]string, error) {
 	if filter == "" {
 		filter = "*"
 	}
+	pattern := escapeGlob(r.prefix) + filter
+	if r.cluster != nil {
+		keys, err := r.clusterKeys(pattern)
+		return r.stripKeys(keys), err
+	}
 	var keys []string
-	_ = r.Do(radix.Cmd(&keys, "KEYS", filter))
-	return keys
+	err := r.Do(radix.Cmd(&keys, "KEYS", pattern))
+	return r.stripKeys(keys), err
 }
 func (r *redis) Touch(keys ...string) {
-	_ = r.Do(radix.Cmd(nil, "TOUCH", keys...))
+	_, _ = r.TouchE(keys...)
+}
+func (r *redis) TouchE(keys ...string) (int, error) {
+	return r.countKeys("TOUCH", r.keys(keys))
 }
 func (r *redis) Type(key string) string {
-	var value string
-	_ = r.Do(radix.Cmd(&value, "TYPE", key))
+	value, _ := r.TypeE(key)
 	return value
 }
+func (r *redis) TypeE(key string) (string, error) {
+	var value string
+	err := r.Do(radix.Cmd(&value, "TYPE", r.key(key)))
+	return value, err
+}
 
 // endregion
 // region - simple
 
 func (r *redis) Set(key string, value any) error {
-	return r.Do(radix.Cmd(nil, "SET", key, fmt.Sprintf("%v", value)))
+	args, err := encodeArgs(r.Codec(), r.key(key), value)
+	if err != nil {
+		return err
+	}
+	return r.Do(radix.Cmd(nil, "SET", args...))
 }
 func (r *redis) Get(key string) (api.Value, error) {
-	var data string
-	var err = r.Do(radix.Cmd(&data, "GET", key))
-	return &redisValue{
-		value: data,
-	}, err
+	var mb = radix.Maybe{Rcv: new(string)}
+	var err = r.Do(radix.Cmd(&mb, "GET", r.key(key)))
+	if err == nil && mb.Null {
+		err = ErrNotFound
+	}
+	return newMaybeValue(&mb, r.Codec()), err
 }
 func (r *redis) Incr(key string) (int, error) {
 	var data int
-	var err = r.Do(radix.Cmd(&data, "INCR", key))
+	var err = r.Do(radix.Cmd(&data, "INCR", r.key(key)))
 	return data, err
 }
 func (r *redis) Decr(key string) (int, error) {
 	var data int
-	var err = r.Do(radix.Cmd(&data, "DECR", key))
+	var err = r.Do(radix.Cmd(&data, "DECR", r.key(key)))
 	return data, err
 }
 
@@ -112,22 +177,25 @@ func (r *redis) Decr(key string) (int, error) {
 
 func (r *redis) PubSub() (conn radix.PubSubConn, err error) {
 	if r.single != nil {
-		return r.singlePubSub()
+		conn, err = r.singlePubSub()
 	} else if r.cluster != nil {
-		return r.clusterPubSub()
+		conn, err = r.clusterPubSub()
 	} else if r.sentinel != nil {
-		return r.sentinelPubSub()
+		conn, err = r.sentinelPubSub()
+	}
+	if conn != nil {
+		conn = r.conns.trackPubSub(conn)
 	}
 	return
 }
 func (r *redis) singlePubSub() (conn radix.PubSubConn, err error) {
-	conn, err = (radix.PersistentPubSubConnConfig{}).New(r.defaultContext(), func() (string, string, error) {
+	conn, err = r.pubSubConfig().New(r.defaultContext(), func() (string, string, error) {
 		return r.single.Addr().Network(), r.single.Addr().String(), nil
 	})
 	return
 }
 func (r *redis) clusterPubSub() (conn radix.PubSubConn, err error) {
-	conn, err = (radix.PersistentPubSubConnConfig{}).New(r.defaultContext(), func() (string, string, error) {
+	conn, err = r.pubSubConfig().New(r.defaultContext(), func() (string, string, error) {
 		clients, err := r.cluster.Clients()
 		if err != nil {
 			return "", "", err
@@ -143,7 +211,7 @@ func (r *redis) clusterPubSub() (conn radix.PubSubConn, err error) {
 	return
 }
 func (r *redis) sentinelPubSub() (conn radix.PubSubConn, err error) {
-	conn, err = (radix.PersistentPubSubConnConfig{}).New(r.defaultContext(), func() (string, string, error) {
+	conn, err = r.pubSubConfig().New(r.defaultContext(), func() (string, string, error) {
 		clients, err := r.sentinel.Clients()
 		if err != nil {
 			return "", "", err
@@ -159,6 +227,39 @@ func (r *redis) sentinelPubSub() (conn radix.PubSubConn, err error) {
 	return
 }
 
+func (r *redis) pubSubConfig() radix.PersistentPubSubConnConfig {
+	reconnecting := new(atomic.Bool)
+	return radix.PersistentPubSubConnConfig{
+		Dialer: r.events.pubSubDialer(r.dialer, reconnecting),
+		Trace: trace.PersistentPubSubTrace{
+			// connection is re-established after every internal error
+			InternalError: func(e trace.PersistentPubSubInternalError) {
+				reconnecting.Store(true)
+				r.Log(api.LevelWarning, "pub/sub connection error, reconnecting", errorField(e.Err))
+				if r.metrics != nil {
+					r.metrics.PubSubReconnected()
+				}
+			},
+		},
+	}
+}
+
+// endregion
+// region - pipeline
+
+func (r *redis) Pipeline(cmds ...radix.Action) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+	return r.Do(newPipelineAction(cmds))
+}
+func (r *redis) Transaction(cmds ...radix.Action) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+	return r.Do(newTransactionAction(cmds))
+}
+
 // endregion
 // region - wrappers
 
@@ -168,12 +269,18 @@ func (r *redis) RMap(key string) api.RMap {
 func (r *redis) RCacheMap(key string) (api.RCacheMap, error) {
 	return NewRCacheMap(key, r)
 }
+func (r *redis) RBucket(key string) api.RBucket {
+	return NewRBucket(key, r)
+}
 func (r *redis) RList(key string) api.RList {
 	return NewRList(key, r)
 }
 func (r *redis) RSet(key string) api.RSet {
 	return NewRSet(key, r)
 }
+func (r *redis) RSortedSet(key string) api.RSortedSet {
+	return NewRSortedSet(key, r)
+}
 
 // endregion
 // region - logger
@@ -203,6 +310,11 @@ func (r *redis) Error(message string, args ...interface{}) {
 		r.logger.Error(message, args...)
 	}
 }
+func (r *redis) Log(level api.LogLevel, message string, fields ...api.LogField) {
+	if r.logger != nil {
+		logStructured(r.logger, level, message, fields...)
+	}
+}
 
 // endregion
 // region - helpers
@@ -211,36 +323,187 @@ func (r *redis) AnyArgs(key string, args ...any) []string {
 	var result []string
 	result = append(result, key)
 	for _, a := range args {
-		switch a.(type) {
-		case string:
-			result = append(result, a.(string))
-		default:
-			result = append(result, fmt.Sprintf("%v", a))
-		}
+		result = append(result, r.anyArg(a))
 	}
 	return result
 }
+
+// anyArg passes strings, numbers and booleans as is, so they remain valid command arguments (indexes, counts, etc.);
+// other values are encoded with codec, values codec fails to encode are formatted with %v
+func (r *redis) anyArg(a any) string {
+	switch v := a.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	switch reflect.ValueOf(a).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%v", a)
+	}
+	data, err := r.Codec().Encode(a)
+	if err != nil {
+		return fmt.Sprintf("%v", a)
+	}
+	return string(data)
+}
 func (r *redis) StrArgs(key string, args ...string) []string {
 	var result []string
 	result = append(result, key)
 	result = append(result, args...)
 	return result
 }
-func (r *redis) Do(cmd radix.Action) error {
-	var err error
-	if r.single != nil {
-		err = r.single.Do(r.defaultContext(), cmd)
+func (r *redis) Codec() api.Codec {
+	return codecOrDefault(r.codec)
+}
+func (r *redis) Do(cmd radix.Action) (err error) {
+	ctx := r.defaultContext()
+	if len(r.interceptors) == 0 && r.tracer == nil && r.slowLog == nil {
+		return r.execute(ctx, cmd)
+	}
+	command, args := newCommand(cmd)
+	if r.slowLog != nil {
+		start := time.Now()
+		defer func() {
+			r.observeSlowCommand(command, start, err)
+		}()
+	}
+	if r.tracer != nil {
+		var end func(error)
+		ctx, end = r.traceCommand(ctx, command, args)
+		defer func() {
+			end(err)
+		}()
+	}
+	if len(r.interceptors) == 0 {
+		return r.execute(ctx, cmd)
+	}
+	return r.handler(0)(ctx, command)
+}
+
+// handler returns handler running interceptors starting from i-th one
+func (r *redis) handler(i int) api.CommandHandler {
+	if i == len(r.interceptors) {
+		return func(ctx context.Context, cmd api.Command) error {
+			return r.execute(ctx, cmd.Action)
+		}
+	}
+	return func(ctx context.Context, cmd api.Command) error {
+		return r.interceptors[i](ctx, cmd, r.handler(i+1))
+	}
+}
+
+// execute issues command applying retry policy
+func (r *redis) execute(ctx context.Context, cmd radix.Action) error {
+	err := r.do(ctx, cmd)
+	if err == nil || r.retryPolicy == nil {
+		return err
+	}
+	name := commandName(cmd)
+	for attempt := 1; err != nil; attempt++ {
+		delay, retry := r.retryPolicy.Retry(name, attempt, err)
+		if !retry || ctx.Err() != nil {
+			return err
+		}
+		r.Log(api.LevelDebug, "retrying command", commandField(name),
+			api.LogField{Key: "attempt", Value: attempt}, api.LogField{Key: "delay", Value: delay}, errorField(err))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		err = r.do(ctx, cmd)
+	}
+	return nil
+}
+func (r *redis) do(ctx context.Context, cmd radix.Action) (err error) {
+	if r.metrics != nil {
+		name, start := commandName(cmd), time.Now()
+		defer func() {
+			if !errors.Is(err, ErrCircuitOpen) {
+				r.metrics.CommandDone(name, time.Since(start), err)
+			}
+		}()
+	}
+	if r.breaker != nil {
+		from, to, openErr := r.breaker.allow()
+		r.logCircuitState(from, to)
+		if openErr != nil {
+			return openErr
+		}
+		callerCtx := ctx
+		defer func() {
+			// commands cancelled by caller say nothing about redis availability
+			if callerCtx.Err() != nil {
+				r.breaker.cancel()
+				return
+			}
+			r.logCircuitState(r.breaker.done(err))
+		}()
+	}
+	// blocking commands wait for data as long as their own timeout says
+	if r.readTimeout > 0 && !blockingCommand(cmd) {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.readTimeout)
+		defer cancel()
+	}
+	defer func() {
+		r.conns.commandDone(err)
+	}()
+	if node := nodeFromContext(ctx); node != nil {
+		err = node.Do(ctx, cmd)
+	} else if r.single != nil {
+		err = r.single.Do(ctx, cmd)
 	} else if r.sentinel != nil {
-		err = r.sentinel.Do(r.defaultContext(), cmd)
+		err = r.sentinel.Do(ctx, cmd)
 	} else if r.cluster != nil {
-		err = r.cluster.Do(context.Background(), cmd)
+		err = r.cluster.Do(ctx, cmd)
 	} else {
 		err = ErrRedisClientNotInitialized
 	}
-	return err
+	return wrapError(err)
 }
 
+// instrumented clients provide metrics receiver and tracer to objects created with them
+type instrumented interface {
+	clientMetrics() api.Metrics
+	startSpan(ctx context.Context, name string, attrs ...api.SpanAttribute) (context.Context, api.Span)
+}
+
+func (r *redis) clientMetrics() api.Metrics {
+	return r.metrics
+}
+func (r *redis) observeSlowCommand(command api.Command, start time.Time, err error) {
+	slow, ok := r.slowLog.observe(command, start, err)
+	if !ok {
+		return
+	}
+	fields := []api.LogField{
+		commandField(slow.Name),
+		{Key: "keys", Value: slow.KeyCount},
+		{Key: "elapsed", Value: slow.Elapsed},
+	}
+	if err != nil {
+		fields = append(fields, errorField(err))
+	}
+	r.Log(api.LevelWarning, "slow command", fields...)
+}
+func (r *redis) logCircuitState(from, to api.CircuitState) {
+	switch {
+	case from == to:
+	case to == api.CircuitOpen:
+		r.Warning("circuit breaker state changed from %s to %s", from, to)
+	default:
+		r.Info("circuit breaker state changed from %s to %s", from, to)
+	}
+}
 func (r *redis) defaultContext() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
 	return context.Background()
 }
 
@@ -1,7 +1,11 @@
 package core
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"github.com/mediocregopher/radix/v4"
 	"github.com/stvp/tempredis"
 	"go.slink.ws/redisson/api"
 	"os"
@@ -15,6 +19,9 @@ const testServerHost = "127.0.0.1"
 
 var server *tempredis.Server
 
+// testBinaryData contains NULs and invalid UTF-8 sequences
+var testBinaryData = []byte{0x00, 0x01, 0xc3, 0x28, 0xff, 0xfe, 0x00, 'a'}
+
 func TestMain(m *testing.M) {
 	var err error
 	_ = os.Remove("dump.rdb")
@@ -47,6 +54,60 @@ func TestNewClient(t *testing.T) {
 		t.Error(err)
 	}
 }
+func TestSelectDb(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	r1, err := NewConfig().
+		WithDb(1).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r1)
+
+	err = r1.Set("TEST_KEY", "TEST_VALUE")
+	if err != nil {
+		t.Error(err)
+	}
+	if r.Exists("TEST_KEY") {
+		t.Errorf("expected non-existent key in db 0")
+	}
+	if !r1.Exists("TEST_KEY") {
+		t.Errorf("expected existent key in db 1")
+	}
+	_, _ = r1.Del("TEST_KEY")
+
+	// connections of client use db it was created with when config is changed afterwards
+	cfg := NewConfig().WithDb(1)
+	r2, err := cfg.NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r2)
+	cfg.WithDb(2)
+	conn, err := r2.(*redis).dialer.Dial(context.Background(), "tcp", fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = conn.Do(context.Background(), radix.Cmd(nil, "SET", "TEST_KEY", "TEST_VALUE"))
+	_ = conn.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r1.Exists("TEST_KEY") {
+		t.Errorf("expected existent key in db 1")
+	}
+	_, _ = r1.Del("TEST_KEY")
+}
 func TestSetGetDelete(t *testing.T) {
 	r, err := createClient()
 	if err != nil {
@@ -74,14 +135,41 @@ func TestSetGetDelete(t *testing.T) {
 		t.Errorf("expected %d, but received %d", 1, i)
 	}
 	v, err = r.Get("TEST_KEY")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected '%v', but received '%v'", ErrNotFound, err)
+	}
+	if !v.IsNil() {
+		t.Errorf("expected nil value")
+	}
+	if v.String() != "" {
+		t.Errorf("expected empty value, but received '%s'", v.String())
+	}
+}
+func TestGetEmptyValue(t *testing.T) {
+	r, err := createClient()
 	if err != nil {
 		t.Error(err)
 	}
-	if v.String() != "" {
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	err = r.Set("TEST_KEY", "")
+	if err != nil {
+		t.Error(err)
+	}
+	v, err := r.Get("TEST_KEY")
+	if err != nil {
+		t.Error(err)
+	}
+	if v.IsNil() {
+		t.Errorf("expected non-nil value")
+	}
+	if !v.IsEmpty() {
 		t.Errorf("expected empty value, but received '%s'", v.String())
 	}
+	_, _ = r.Del("TEST_KEY")
 }
-func TestExistsExpireDelete(t *testing.T) {
+func TestWithContext(t *testing.T) {
 	r, err := createClient()
 	if err != nil {
 		t.Error(err)
@@ -90,10 +178,154 @@ func TestExistsExpireDelete(t *testing.T) {
 		_ = r.Close()
 	}(r)
 
-	value, err := r.Get("TEST_KEY")
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := r.WithContext(ctx)
+	if rc.Context() != ctx {
+		t.Errorf("expected view context")
+	}
+	err = rc.Set("TEST_KEY", "TEST_VALUE")
+	if err != nil {
+		t.Error(err)
+	}
+
+	cancel()
+	_, err = rc.Get("TEST_KEY")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected '%v', but received '%v'", context.Canceled, err)
+	}
+	err = NewRMap("TEST_MAP", r).WithContext(ctx).Set("key", "value")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected '%v', but received '%v'", context.Canceled, err)
+	}
+
+	v, err := r.Get("TEST_KEY")
+	if err != nil {
+		t.Error(err)
+	}
+	if v.String() != "TEST_VALUE" {
+		t.Errorf("expected '%s', but received '%s'", "TEST_VALUE", v.String())
+	}
+	_, _ = r.Del("TEST_KEY")
+}
+func TestSetGetBinary(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	err = r.Set("TEST_KEY", testBinaryData)
+	if err != nil {
+		t.Error(err)
+	}
+	v, err := r.Get("TEST_KEY")
+	if err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(v.AsBytes(), testBinaryData) {
+		t.Errorf("expected '%v', but received '%v'", testBinaryData, v.AsBytes())
+	}
+	_, _ = r.Del("TEST_KEY")
+}
+func TestUnixSocket(t *testing.T) {
+	r, err := NewConfig().
+		WithName("TEST-UNIX-CLIENT").
+		WithDb(1).
+		NewSingleUnix(server.Socket())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	err = r.Set("TEST_KEY", "TEST_VALUE")
+	if err != nil {
+		t.Error(err)
+	}
+	v, err := r.Get("TEST_KEY")
+	if err != nil {
+		t.Error(err)
+	}
+	if v.String() != "TEST_VALUE" {
+		t.Errorf("expected '%s', but received '%s'", "TEST_VALUE", v.String())
+	}
+	_, _ = r.Del("TEST_KEY")
+
+	psconn, err := r.PubSub()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = psconn.Subscribe(context.Background(), "TEST_CHANNEL")
+	if err != nil {
+		t.Error(err)
+	}
+	// SUBSCRIBE does not wait for confirmation, so publish until there is a receiver
+	var receivers int
+	for i := 0; i < 20 && receivers == 0 && err == nil; i++ {
+		err = r.Do(radix.Cmd(&receivers, "PUBLISH", "TEST_CHANNEL", "TEST_MESSAGE"))
+		if receivers == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if err != nil {
+		t.Error(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	msg, err := psconn.Next(ctx)
+	cancel()
+	if err != nil {
+		t.Error(err)
+	}
+	if string(msg.Message) != "TEST_MESSAGE" {
+		t.Errorf("expected '%s', but received '%s'", "TEST_MESSAGE", msg.Message)
+	}
+	_ = psconn.Close()
+
+	err = r.EnableKeyEventNotifications()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.DisableKeyEventNotifications()
+	}(r)
+	m, err := NewRCacheMap("TEST_UNIX_CACHE_MAP", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Destroy()
+
+	// change made by another client is delivered via keyspace notification
+	err = NewRMap("TEST_UNIX_CACHE_MAP", r).Set("key1", "value1")
+	if err != nil {
+		t.Error(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	value, ok := m.Get("key1")
+	for !ok && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+		value, ok = m.Get("key1")
+	}
+	if !ok || value.String() != "value1" {
+		t.Errorf("expected '%s', but received '%s'", "value1", value.String())
+	}
+	_, _ = r.Del("TEST_UNIX_CACHE_MAP")
+}
+func TestExistsExpireDelete(t *testing.T) {
+	r, err := createClient()
 	if err != nil {
 		t.Error(err)
 	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	value, err := r.Get("TEST_KEY")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected '%v', but received '%v'", ErrNotFound, err)
+	}
 	if !value.IsEmpty() {
 		t.Errorf("expected empty value, received '%s'", value)
 	}
@@ -131,8 +363,8 @@ func TestExistsExpireDelete(t *testing.T) {
 	time.Sleep(250 * time.Millisecond)
 
 	value, err = r.Get("TEST_KEY")
-	if err != nil {
-		t.Error(err)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected '%v', but received '%v'", ErrNotFound, err)
 	}
 	if !value.IsEmpty() {
 		t.Errorf("expected empty value, received '%s'", value)
@@ -193,8 +425,8 @@ func TestIncrDecrDelete(t *testing.T) {
 	}
 
 	v, err := r.Get("TEST_KEY")
-	if err != nil {
-		t.Error(err)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected '%v', but received '%v'", ErrNotFound, err)
 	}
 	if v.String() != "" {
 		t.Errorf("expected empty value, but received '%s'", v.String())
@@ -239,6 +471,14 @@ func TestKeys(t *testing.T) {
 		t.Errorf("expected '%s', received '%s'", "TEST_KEY_2", keys[0])
 	}
 
+	keys, err = r.KeysE("TEST_KEY_*")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("expected two items")
+	}
+
 	_, _ = r.Del("TEST_KEY_1", "TEST_KEY_2")
 }
 func TestType(t *testing.T) {
@@ -257,6 +497,20 @@ func TestType(t *testing.T) {
 	if tp != "string" {
 		t.Errorf("expected 'string', received '%s'", tp)
 	}
+	tp, err = r.TypeE("TEST_KEY")
+	if err != nil {
+		t.Error(err)
+	}
+	if tp != "string" {
+		t.Errorf("expected 'string', received '%s'", tp)
+	}
+	n, err := r.TouchE("TEST_KEY", "TEST_KEY_MISSING")
+	if err != nil {
+		t.Error(err)
+	}
+	if n != 1 {
+		t.Errorf("expected %d, received %d", 1, n)
+	}
 	_, _ = r.Del("TEST_KEY")
 }
 func TestAnyArgs(t *testing.T) {
@@ -284,6 +538,24 @@ func TestAnyArgs(t *testing.T) {
 	if v[4] != "yes" {
 		t.Errorf("expected 'yes', received '%v'", v[4])
 	}
+
+	v = r.AnyArgs("key", []byte{0x00, 0xff})
+	if v[1] != "\x00\xff" {
+		t.Errorf("expected '%v', received '%v'", []byte{0x00, 0xff}, []byte(v[1]))
+	}
+
+	// complex values are encoded with codec, numbers remain numbers
+	rj, err := NewConfig().WithCodec(NewJsonCodec()).NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(rj)
+	v = rj.AnyArgs("key", 10, map[string]int{"a": 1}, []string{"b"})
+	if v[1] != "10" || v[2] != `{"a":1}` || v[3] != `["b"]` {
+		t.Errorf("expected '%v', received '%v'", []string{"key", "10", `{"a":1}`, `["b"]`}, v)
+	}
 }
 func TestStrArgs(t *testing.T) {
 	r, err := createClient()
@@ -305,3 +577,83 @@ func TestStrArgs(t *testing.T) {
 		t.Errorf("expected 'arg2', received '%v'", v[2])
 	}
 }
+func TestPipeline(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	var set string
+	var incr int
+	var get string
+	err = r.Pipeline(
+		radix.Cmd(&set, "SET", "TEST_PIPELINE", "1"),
+		radix.Cmd(&incr, "INCR", "TEST_PIPELINE"),
+		radix.Cmd(&get, "GET", "TEST_PIPELINE"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set != "OK" || incr != 2 || get != "2" {
+		t.Errorf("unexpected pipeline results: %s %d %s", set, incr, get)
+	}
+	if err = r.Pipeline(); err != nil {
+		t.Errorf("expected empty pipeline to succeed: %v", err)
+	}
+}
+func TestTransaction(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	var incr1, incr2 int
+	err = r.Transaction(
+		radix.Cmd(nil, "SET", "TEST_TRANSACTION", "1"),
+		radix.Cmd(&incr1, "INCR", "TEST_TRANSACTION"),
+		radix.Cmd(&incr2, "INCR", "TEST_TRANSACTION"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if incr1 != 2 || incr2 != 3 {
+		t.Errorf("unexpected transaction results: %d %d", incr1, incr2)
+	}
+	v, err := r.Get("TEST_TRANSACTION")
+	if err != nil || v.String() != "3" {
+		t.Errorf("unexpected value after transaction: %v %v", v, err)
+	}
+}
+func TestReadTimeoutBlocking(t *testing.T) {
+	r, err := NewConfig().
+		WithReadTimeout(50 * time.Millisecond).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	// blocking command waits longer than read timeout
+	var reply []string
+	err = r.Do(radix.Cmd(&reply, "BLPOP", "TEST_BLOCKING_KEY", "0.2"))
+	if err != nil {
+		t.Errorf("expected no error, received '%v'", err)
+	}
+	if len(reply) != 0 {
+		t.Errorf("expected empty reply, received '%v'", reply)
+	}
+
+	err = r.Do(radix.Cmd(nil, "DEBUG", "SLEEP", "0.2"))
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected '%v', received '%v'", ErrTimeout, err)
+	}
+	time.Sleep(200 * time.Millisecond)
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+const defaultRetryMinBackoff = 50 * time.Millisecond
+const defaultRetryMaxBackoff = 2 * time.Second
+const defaultRetryJitter = 0.5
+
+// defaultNeverRetryCommands are not idempotent: repeating them after lost reply could apply them twice
+var defaultNeverRetryCommands = []string{
+	"APPEND", "BLMOVE", "BLMPOP", "BLPOP", "BRPOP", "BRPOPLPUSH", "BZMPOP", "BZPOPMAX", "BZPOPMIN",
+	"DECR", "DECRBY", "EVAL", "EVALSHA", "EVAL_RO", "EVALSHA_RO", "EXEC", "FCALL", "GETDEL", "GETSET",
+	"HINCRBY", "HINCRBYFLOAT", "INCR", "INCRBY", "INCRBYFLOAT", "LINSERT", "LMOVE", "LMPOP", "LPOP",
+	"LPUSH", "LPUSHX", "MULTI", "PIPELINE", "PUBLISH", "RPOP", "RPOPLPUSH", "RPUSH", "RPUSHX", "SMOVE",
+	"SPOP", "SPUBLISH", "XADD", "XAUTOCLAIM", "XCLAIM", "XREADGROUP", "ZINCRBY", "ZMPOP", "ZPOPMAX", "ZPOPMIN",
+}
+
+// NewRetryPolicy creates policy retrying idempotent commands failed with connection, timeout or loading errors
+// using exponential backoff with jitter; maxAttempts limits total amount of attempts including the first one
+func NewRetryPolicy(maxAttempts int) *retryPolicy {
+	p := &retryPolicy{
+		maxAttempts: maxAttempts,
+		minBackoff:  defaultRetryMinBackoff,
+		maxBackoff:  defaultRetryMaxBackoff,
+		jitter:      defaultRetryJitter,
+		neverRetry:  make(map[string]bool),
+	}
+	return p.WithNeverRetry(defaultNeverRetryCommands...)
+}
+
+type retryPolicy struct {
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	jitter      float64
+	neverRetry  map[string]bool
+}
+
+// WithBackoff sets delay before the first retry; delay is doubled on every next retry up to max
+func (p *retryPolicy) WithBackoff(min, max time.Duration) *retryPolicy {
+	p.minBackoff = min
+	p.maxBackoff = max
+	return p
+}
+
+// WithJitter sets fraction (0..1) of the delay which is randomly subtracted from it
+func (p *retryPolicy) WithJitter(jitter float64) *retryPolicy {
+	p.jitter = min(max(jitter, 0), 1)
+	return p
+}
+
+// WithNeverRetry marks commands which should never be retried
+func (p *retryPolicy) WithNeverRetry(commands ...string) *retryPolicy {
+	for _, cmd := range commands {
+		p.neverRetry[strings.ToUpper(cmd)] = true
+	}
+	return p
+}
+
+// WithRetry removes commands from never-retry list, e.g. when application does not care about duplicates
+func (p *retryPolicy) WithRetry(commands ...string) *retryPolicy {
+	for _, cmd := range commands {
+		delete(p.neverRetry, strings.ToUpper(cmd))
+	}
+	return p
+}
+
+func (p *retryPolicy) Retry(command string, attempt int, err error) (time.Duration, bool) {
+	// unknown actions (pipelines, scripts, etc) may contain non-idempotent commands
+	if command == "" || p.neverRetry[strings.ToUpper(command)] || attempt >= p.maxAttempts {
+		return 0, false
+	}
+	if !errors.Is(err, ErrConnection) && !errors.Is(err, ErrTimeout) && !errors.Is(err, ErrLoading) {
+		return 0, false
+	}
+	return p.backoff(attempt), true
+}
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.minBackoff
+	for i := 1; i < attempt && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, p.maxBackoff)
+	return delay - time.Duration(rand.Float64()*p.jitter*float64(delay))
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"testing"
+	"time"
+)
+
+type countingRetryPolicy struct {
+	policy   api.RetryPolicy
+	commands []string
+}
+
+func (p *countingRetryPolicy) Retry(command string, attempt int, err error) (time.Duration, bool) {
+	p.commands = append(p.commands, command)
+	return p.policy.Retry(command, attempt, err)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	p := NewRetryPolicy(4).WithBackoff(10*time.Millisecond, 25*time.Millisecond).WithJitter(0)
+
+	for attempt, expected := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond} {
+		delay, ok := p.Retry("GET", attempt+1, ErrTimeout)
+		if !ok {
+			t.Errorf("expected retry for attempt %d", attempt+1)
+		}
+		if delay != expected {
+			t.Errorf("expected '%v', received '%v'", expected, delay)
+		}
+	}
+	if _, ok := p.Retry("GET", 4, ErrTimeout); ok {
+		t.Errorf("expected no retry after max attempts")
+	}
+	for _, cmd := range []string{"INCR", "lpush", ""} {
+		if _, ok := p.Retry(cmd, 1, ErrConnection); ok {
+			t.Errorf("expected no retry for '%s'", cmd)
+		}
+	}
+	if _, ok := p.Retry("GET", 1, ErrWrongType); ok {
+		t.Errorf("expected no retry for reply error")
+	}
+	if _, ok := p.Retry("GET", 1, errors.New("unknown")); ok {
+		t.Errorf("expected no retry for unclassified error")
+	}
+
+	p.WithNeverRetry("hset").WithRetry("INCR")
+	if _, ok := p.Retry("HSET", 1, ErrConnection); ok {
+		t.Errorf("expected no retry for '%s'", "HSET")
+	}
+	if _, ok := p.Retry("INCR", 1, ErrConnection); !ok {
+		t.Errorf("expected retry for '%s'", "INCR")
+	}
+
+	p.WithJitter(1)
+	for i := 0; i < 10; i++ {
+		delay, _ := p.Retry("GET", 1, ErrLoading)
+		if delay < 0 || delay > 10*time.Millisecond {
+			t.Errorf("expected delay within [0, %v], received '%v'", 10*time.Millisecond, delay)
+		}
+	}
+}
+func TestRetryCommands(t *testing.T) {
+	policy := &countingRetryPolicy{
+		policy: NewRetryPolicy(3).WithBackoff(time.Millisecond, time.Millisecond),
+	}
+	r, err := NewConfig().
+		WithReadTimeout(50 * time.Millisecond).
+		WithRetryPolicy(policy).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	err = r.Do(radix.Cmd(nil, "DEBUG", "SLEEP", "0.2"))
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected '%v', received '%v'", ErrTimeout, err)
+	}
+	if len(policy.commands) != 3 || policy.commands[0] != "DEBUG" {
+		t.Errorf("expected 3 retry decisions for '%s', received '%v'", "DEBUG", policy.commands)
+	}
+
+	// commands marked as never-retry are issued once, without waiting for backoff
+	policy.policy.(*retryPolicy).WithNeverRetry("DEBUG").WithBackoff(time.Second, time.Second)
+	policy.commands = nil
+	start := time.Now()
+	err = r.Do(radix.Cmd(nil, "DEBUG", "SLEEP", "0.2"))
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected '%v', received '%v'", ErrTimeout, err)
+	}
+	if len(policy.commands) != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("expected single attempt, received '%v' in %v", policy.commands, time.Since(start))
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	policy.commands = nil
+	_, err = r.Incr("TEST_RETRY_KEY")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(policy.commands) != 0 {
+		t.Errorf("expected no retry decisions, received '%v'", policy.commands)
+	}
+	_, _ = r.Del("TEST_RETRY_KEY")
+}
@@ -1,49 +1,78 @@
 package core
 
 import (
+	"context"
 	"github.com/mediocregopher/radix/v4"
 	"go.slink.ws/redisson/api"
 	"reflect"
 )
 
 func NewRList(key string, client api.Redis) api.RList {
+	return NewRListWithCodec(key, client, client.Codec())
+}
+func NewRListWithCodec(key string, client api.Redis, codec api.Codec) api.RList {
 	return &rlist{
 		client: client,
-		key:    key,
+		key:    namespacedKey(client, key),
+		codec:  codecOrDefault(codec),
 	}
 }
 
 type rlist struct {
 	client api.Redis
 	key    string
+	codec  api.Codec
 }
 
 func (l *rlist) Len() int {
-	var value int
-	_ = l.client.Do(radix.Cmd(&value, "LLEN", l.key))
+	value, _ := l.LenE()
 	return value
 }
+func (l *rlist) LenE() (int, error) {
+	var value int
+	err := l.client.Do(radix.Cmd(&value, "LLEN", l.key))
+	return value, err
+}
 func (l *rlist) LPush(items ...any) error {
-	return l.client.Do(radix.Cmd(nil, "LPUSH", l.client.AnyArgs(l.key, items...)...))
+	return l.push("LPUSH", items...)
 }
 func (l *rlist) LPushRO(items ...any) error {
 	i2 := make([]any, len(items))
 	copy(i2, items)
 	ReverseSlice(i2)
-	return l.client.Do(radix.Cmd(nil, "LPUSH", l.client.AnyArgs(l.key, i2...)...))
+	return l.push("LPUSH", i2...)
 }
 func (l *rlist) LPop() (api.Value, error) {
-	var value string
-	err := l.client.Do(radix.Cmd(&value, "LPOP", l.key))
-	return NewValue(value), err
+	return l.pop("LPOP")
 }
 func (l *rlist) RPush(items ...any) error {
-	return l.client.Do(radix.Cmd(nil, "RPUSH", l.client.AnyArgs(l.key, items...)...))
+	return l.push("RPUSH", items...)
 }
 func (l *rlist) RPop() (api.Value, error) {
-	var value string
-	err := l.client.Do(radix.Cmd(&value, "RPOP", l.key))
-	return NewValue(value), err
+	return l.pop("RPOP")
+}
+func (l *rlist) WithContext(ctx context.Context) api.RList {
+	return &rlist{
+		client: l.client.WithContext(ctx),
+		key:    l.key,
+		codec:  l.codec,
+	}
+}
+
+func (l *rlist) push(cmd string, items ...any) error {
+	args, err := encodeArgs(l.codec, l.key, items...)
+	if err != nil {
+		return err
+	}
+	return l.client.Do(radix.Cmd(nil, cmd, args...))
+}
+func (l *rlist) pop(cmd string) (api.Value, error) {
+	mb := radix.Maybe{Rcv: new(string)}
+	err := l.client.Do(radix.Cmd(&mb, cmd, l.key))
+	if err == nil && mb.Null {
+		err = ErrNotFound
+	}
+	return newMaybeValue(&mb, l.codec), err
 }
 
 func ReverseSlice(s interface{}) {
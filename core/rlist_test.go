@@ -1,6 +1,8 @@
 package core
 
 import (
+	"bytes"
+	"errors"
 	"go.slink.ws/redisson/api"
 	"testing"
 )
@@ -152,3 +154,56 @@ func TestRListLRO(t *testing.T) {
 	_, _ = r.Del("TEST_LIST")
 
 }
+func TestRListBinary(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	l := NewRList("TEST_LIST", r)
+
+	_ = l.LPush(testBinaryData)
+
+	v, err := l.LPop()
+	if err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(v.AsBytes(), testBinaryData) {
+		t.Errorf("expected '%v', received '%v'", testBinaryData, v.AsBytes())
+	}
+
+	_, _ = r.Del("TEST_LIST")
+
+}
+func TestRListEmpty(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	l := NewRList("TEST_LIST", r)
+
+	v, err := l.LPop()
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected '%v', received '%v'", ErrNotFound, err)
+	}
+	if !v.IsNil() {
+		t.Errorf("expected nil value, received '%v'", v)
+	}
+
+	_ = l.RPush("")
+	v, err = l.RPop()
+	if err != nil {
+		t.Error(err)
+	}
+	if v.IsNil() || !v.IsEmpty() {
+		t.Errorf("expected empty value, received '%v'", v)
+	}
+
+}
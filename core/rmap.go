@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/mediocregopher/radix/v4"
 	"go.slink.ws/redisson/api"
+	"iter"
 	"sync"
 	"time"
 )
@@ -16,52 +17,85 @@ import (
 // region - RMap
 
 func NewRMap(key string, client api.Redis) api.RMap {
+	return NewRMapWithCodec(key, client, client.Codec())
+}
+func NewRMapWithCodec(key string, client api.Redis, codec api.Codec) api.RMap {
 	return &rmap{
 		client: client,
-		key:    key,
+		key:    namespacedKey(client, key),
+		codec:  codecOrDefault(codec),
 	}
 }
 
 type rmap struct {
 	client api.Redis
 	key    string
+	codec  api.Codec
 }
 
 func (m *rmap) Set(key string, value any) error {
-	return m.client.Do(radix.Cmd(nil, "HSET", m.key, key, fmt.Sprintf("%v", value)))
+	args, err := encodeArgs(m.codec, key, value)
+	if err != nil {
+		return err
+	}
+	return m.client.Do(radix.Cmd(nil, "HSET", m.client.StrArgs(m.key, args...)...))
 }
 func (m *rmap) Get(key string) (api.Value, bool) {
-	var value string
-	var ok = true
-	err := m.client.Do(radix.Cmd(&value, "HGET", m.key, key))
+	value, ok, err := m.GetE(key)
+	if err != nil {
+		logStructured(m.client, api.LevelWarning, "RMap get error",
+			keyField(m.key), commandField("HGET"), errorField(err))
+	}
+	return value, ok
+}
+func (m *rmap) GetE(key string) (api.Value, bool, error) {
+	mb := radix.Maybe{Rcv: new(string)}
+	err := m.client.Do(radix.Cmd(&mb, "HGET", m.key, key))
 	if err != nil {
-		m.client.Warning("RMap get error: %s", err.Error())
-		ok = false
+		return newNilValue(m.codec), false, err
 	}
-	return NewValue(value), ok
+	return newMaybeValue(&mb, m.codec), !mb.Null, nil
 }
 func (m *rmap) Del(keys ...string) error {
 	return m.client.Do(radix.Cmd(nil, "HDEL", m.client.StrArgs(m.key, keys...)...))
 }
 func (m *rmap) Keys() []string {
+	result, _ := m.KeysE()
+	return result
+}
+func (m *rmap) KeysE() ([]string, error) {
 	var result []string
-	_ = m.client.Do(radix.Cmd(&result, "HKEYS", m.key))
+	err := m.client.Do(radix.Cmd(&result, "HKEYS", m.key))
 	if result == nil {
 		result = []string{}
 	}
-	return result
+	return result, err
 }
 func (m *rmap) Entries() []api.MapEntry {
+	values, _ := m.EntriesE()
+	return values
+}
+func (m *rmap) EntriesE() ([]api.MapEntry, error) {
 	var result map[string]string
-	_ = m.client.Do(radix.Cmd(&result, "HGETALL", m.key))
+	err := m.client.Do(radix.Cmd(&result, "HGETALL", m.key))
 	var values []api.MapEntry
 	for k, v := range result {
 		values = append(values, api.MapEntry{
 			Key:   k,
-			Value: NewValue(v),
+			Value: newCodecValue(v, m.codec),
 		})
 	}
-	return values
+	return values, err
+}
+func (m *rmap) Scan(ctx context.Context, match string, count int) iter.Seq2[api.MapEntry, error] {
+	return hscan(ctx, m.client, m.key, m.codec, match, count)
+}
+func (m *rmap) WithContext(ctx context.Context) api.RMap {
+	return &rmap{
+		client: m.client.WithContext(ctx),
+		key:    m.key,
+		codec:  m.codec,
+	}
 }
 
 // endregion
@@ -83,6 +117,7 @@ const (
 type rcachemap struct {
 	client    api.Redis
 	key       string
+	codec     api.Codec
 	rwMutex   sync.RWMutex
 	syncMutex sync.RWMutex
 	syncState syncState
@@ -90,12 +125,18 @@ type rcachemap struct {
 	redisChn  chan radix.PubSubMessage
 	doneChn   chan *struct{}
 	psconn    radix.PubSubConn
+
+	stoppedChn chan struct{}
 }
 
 func NewRCacheMap(key string, client api.Redis) (api.RCacheMap, error) {
+	return NewRCacheMapWithCodec(key, client, client.Codec())
+}
+func NewRCacheMapWithCodec(key string, client api.Redis, codec api.Codec) (api.RCacheMap, error) {
 	m := &rcachemap{
 		client:    client,
-		key:       key,
+		key:       namespacedKey(client, key),
+		codec:     codecOrDefault(codec),
 		syncState: syncNeeded,
 		cache:     make(map[string]api.Value),
 		redisChn:  make(chan radix.PubSubMessage, 1),
@@ -103,17 +144,24 @@ func NewRCacheMap(key string, client api.Redis) (api.RCacheMap, error) {
 	}
 	err := m.run()
 	if err != nil {
-		m.client.Error("could not start subscription for key %s", key)
+		m.log(api.LevelError, "could not start RCacheMap subscription", errorField(err))
 		m.Destroy()
 	}
 	return m, err
 }
 
 func (m *rcachemap) Set(key string, value any) error {
+	return m.set(m.client, key, value)
+}
+func (m *rcachemap) set(client api.Redis, key string, value any) error {
 	m.rwMutex.Lock()
 	defer m.rwMutex.Unlock()
 	m.client.Debug("+ set start: %s", key)
-	err := m.client.Do(radix.Cmd(nil, "HSET", m.key, key, fmt.Sprintf("%v", value)))
+	args, err := encodeArgs(m.codec, key, value)
+	if err != nil {
+		return err
+	}
+	err = client.Do(radix.Cmd(nil, "HSET", client.StrArgs(m.key, args...)...))
 	m.syncState = syncNeeded
 	m.client.Debug("+ set end: %s %d", key, m.syncState)
 	return err
@@ -123,13 +171,19 @@ func (m *rcachemap) Get(key string) (api.Value, bool) {
 	m.rwMutex.RLock()
 	defer m.rwMutex.RUnlock()
 	v, ok := m.cache[key]
+	if !ok {
+		return newNilValue(m.codec), false
+	}
 	return v, ok
 }
 func (m *rcachemap) Del(keys ...string) error {
+	return m.del(m.client, keys...)
+}
+func (m *rcachemap) del(client api.Redis, keys ...string) error {
 	m.rwMutex.Lock()
 	defer m.rwMutex.Unlock()
 	m.client.Debug("- del start: %s", keys)
-	err := m.client.Do(radix.Cmd(nil, "HDEL", m.client.StrArgs(m.key, keys...)...))
+	err := client.Do(radix.Cmd(nil, "HDEL", client.StrArgs(m.key, keys...)...))
 	m.syncState = syncNeeded
 	m.client.Debug("- del end: %s", keys)
 	return err
@@ -160,8 +214,33 @@ func (m *rcachemap) Entries() []api.MapEntry {
 	}
 	return result
 }
+func (m *rcachemap) GetE(key string) (api.Value, bool, error) {
+	value, ok := m.Get(key)
+	return value, ok, nil
+}
+func (m *rcachemap) KeysE() ([]string, error) {
+	return m.Keys(), nil
+}
+func (m *rcachemap) EntriesE() ([]api.MapEntry, error) {
+	return m.Entries(), nil
+}
+
+// Scan iterates map entries in redis rather than in local cache, so pattern is matched by redis
+func (m *rcachemap) Scan(ctx context.Context, match string, count int) iter.Seq2[api.MapEntry, error] {
+	return hscan(ctx, m.client, m.key, m.codec, match, count)
+}
+func (m *rcachemap) WithContext(ctx context.Context) api.RMap {
+	return &rcachemapView{
+		rcachemap: m,
+		client:    m.client.WithContext(ctx),
+	}
+}
 func (m *rcachemap) Destroy() {
 	m.doneChn <- &struct{}{}
+	if m.stoppedChn != nil {
+		// pub/sub connection is re-established if it is closed while background process reads from it
+		<-m.stoppedChn
+	}
 	if m.psconn != nil {
 		_ = m.psconn.PUnsubscribe(context.Background())
 		_ = m.psconn.Close()
@@ -169,29 +248,27 @@ func (m *rcachemap) Destroy() {
 }
 
 func (m *rcachemap) run() error {
-	m.client.Debug("starting RCacheMap background process for %s", m.key)
+	m.log(api.LevelDebug, "starting RCacheMap background process")
 	var err error
 	// enable key event notification explicitly
 	m.psconn, err = m.client.PubSub()
 	if err != nil {
 		return err
 	}
-	err = m.psconn.PSubscribe(context.Background(), fmt.Sprintf(keySpaceTopicFormat, m.key))
+	err = m.psconn.PSubscribe(context.Background(), fmt.Sprintf(keySpaceTopicFormat, escapeGlob(m.key)))
 	if err != nil {
 		return err
 	}
+	m.stoppedChn = make(chan struct{})
 	go func() {
 		timer := time.NewTimer(100 * time.Millisecond)
 		for {
 			select {
-			case v := <-m.doneChn:
-				if v != nil {
-					timer.Stop()
-					m.client.Debug("stopping RCacheMap background process for %s", m.key)
-					close(m.doneChn)
-					close(m.redisChn)
-				}
-				break
+			case <-m.doneChn:
+				timer.Stop()
+				m.log(api.LevelDebug, "stopping RCacheMap background process")
+				close(m.stoppedChn)
+				return
 			case msg := <-m.redisChn:
 				if msg.Channel != "" {
 					m.handleMessage(msg)
@@ -219,26 +296,52 @@ func (m *rcachemap) sync() {
 	m.syncMutex.Lock()
 	defer m.syncMutex.Unlock()
 	m.syncState = syncInProgress
+	var err error
+	client := m.client
+	if ic, ok := m.client.(instrumented); ok {
+		if ic.clientMetrics() != nil {
+			start := time.Now()
+			defer func() {
+				ic.clientMetrics().CacheMapSynced(m.key, time.Since(start), err)
+			}()
+		}
+		// sync commands are traced as children of sync span
+		ctx, span := ic.startSpan(m.client.Context(), spanCacheMapSyncName,
+			api.SpanAttribute{Key: spanAttrRedisKey, Value: m.key})
+		defer func() {
+			span.End(err)
+		}()
+		client = m.client.WithContext(ctx)
+	}
 	var keys []string
-	err := m.client.Do(radix.Cmd(&keys, "HKEYS", m.key))
+	err = client.Do(radix.Cmd(&keys, "HKEYS", m.key))
 	if err != nil {
-		m.client.Warning("sync keys error: %s", err.Error())
+		m.log(api.LevelWarning, "sync keys error", commandField("HKEYS"), errorField(err))
 	} else {
 		m.cache = make(map[string]api.Value)
 		for _, key := range keys {
-			var value string
-			err := m.client.Do(radix.Cmd(&value, "HGET", m.key, key))
+			mb := radix.Maybe{Rcv: new(string)}
+			err := client.Do(radix.Cmd(&mb, "HGET", m.key, key))
 			if err != nil {
-				m.client.Warning("sync error for key %s: %s", key, err.Error())
+				m.log(api.LevelWarning, "sync error", fieldField(key), commandField("HGET"), errorField(err))
+				continue
+			}
+			if mb.Null {
 				continue
 			}
-			m.client.Debug("cache map %s: sync key %s=%v", m.key, key, value)
-			m.cache[key] = NewValue(value)
+			value := newMaybeValue(&mb, m.codec)
+			m.log(api.LevelDebug, "sync field", fieldField(key), api.LogField{Key: "value", Value: value})
+			m.cache[key] = value
 		}
 	}
 	m.client.Debug("sync end")
 	m.syncState = syncComplete
 }
+
+// log logs message with cache map key field
+func (m *rcachemap) log(level api.LogLevel, message string, fields ...api.LogField) {
+	logStructured(m.client, level, message, append([]api.LogField{keyField(m.key)}, fields...)...)
+}
 func (m *rcachemap) checkSubscription() {
 	//m.client.Warning("subscription check")
 	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
@@ -247,7 +350,7 @@ func (m *rcachemap) checkSubscription() {
 	if errors.Is(err, context.DeadlineExceeded) {
 		//m.client.Debug("subscription check timeout")
 	} else if err != nil {
-		m.client.Warning("subscription check error: %s", err.Error())
+		m.log(api.LevelWarning, "subscription check error", errorField(err))
 	} else {
 		m.redisChn <- msg
 	}
@@ -277,7 +380,7 @@ func (m *rcachemap) wait() {
 	for {
 		select {
 		case <-time.After(5 * time.Second):
-			m.client.Notice("sync wait timeout")
+			m.log(api.LevelNotice, "sync wait timeout")
 			m.syncState = syncComplete
 			return
 		default:
@@ -289,4 +392,20 @@ func (m *rcachemap) wait() {
 	}
 }
 
+// rcachemapView issues RCacheMap writes with its own client while sharing local cache with the map
+type rcachemapView struct {
+	*rcachemap
+	client api.Redis
+}
+
+func (v *rcachemapView) Set(key string, value any) error {
+	return v.set(v.client, key, value)
+}
+func (v *rcachemapView) Del(keys ...string) error {
+	return v.del(v.client, keys...)
+}
+func (v *rcachemapView) WithContext(ctx context.Context) api.RMap {
+	return v.rcachemap.WithContext(ctx)
+}
+
 //endregion
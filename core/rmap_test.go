@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"fmt"
 	"go.slink.ws/redisson/api"
 	"testing"
@@ -27,9 +28,13 @@ func TestRMap(t *testing.T) {
 	}
 
 	value, ok := m.Get("key")
-	if !ok {
+	if ok || !value.IsNil() {
 		t.Errorf("expected no value, received '%v'", value)
 	}
+	value, ok, err = m.GetE("key")
+	if ok || !value.IsNil() || err != nil {
+		t.Errorf("expected no value, received '%v' '%v'", value, err)
+	}
 
 	err = m.Set("key", "value")
 	if err != nil {
@@ -57,10 +62,21 @@ func TestRMap(t *testing.T) {
 	}
 
 	value, ok = m.Get("key")
-	if !ok {
+	if ok || !value.IsNil() {
 		t.Errorf("expected no value, received '%v'", value)
 	}
 
+	err = m.Set("key", "")
+	if err != nil {
+		t.Error(err)
+	}
+	value, ok = m.Get("key")
+	if !ok || value.IsNil() || !value.IsEmpty() {
+		t.Errorf("expected empty value, received '%v'", value)
+	}
+
+	_, _ = r.Del("TEST_MAP")
+
 }
 func TestRMapKeysEntries(t *testing.T) {
 
@@ -204,3 +220,76 @@ func TestRCacheMap(t *testing.T) {
 	m.Destroy()
 
 }
+func TestRMapCodec(t *testing.T) {
+
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	m := NewRMapWithCodec("TEST_MAP", r, NewJsonCodec())
+
+	value := testCodecStruct{
+		Name:  "test",
+		Count: 3,
+		Tags:  []string{"a", "b"},
+	}
+	err = m.Set("key", value)
+	if err != nil {
+		t.Error(err)
+	}
+
+	v, ok := m.Get("key")
+	if !ok {
+		t.Errorf("expected existing key '%s'", "key")
+	}
+	var result testCodecStruct
+	err = v.Decode(&result)
+	if err != nil {
+		t.Error(err)
+	}
+	if result.Name != value.Name || result.Count != value.Count || len(result.Tags) != len(value.Tags) {
+		t.Errorf("expected '%v', received '%v'", value, result)
+	}
+
+	for _, e := range m.Entries() {
+		err = e.Value.Decode(&result)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	_, _ = r.Del("TEST_MAP")
+
+}
+func TestRMapBinary(t *testing.T) {
+
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	m := NewRMap("TEST_MAP", r)
+
+	err = m.Set("key", testBinaryData)
+	if err != nil {
+		t.Error(err)
+	}
+
+	v, ok := m.Get("key")
+	if !ok {
+		t.Errorf("expected existing key '%s'", "key")
+	}
+	if !bytes.Equal(v.AsBytes(), testBinaryData) {
+		t.Errorf("expected '%v', received '%v'", testBinaryData, v.AsBytes())
+	}
+
+	_, _ = r.Del("TEST_MAP")
+
+}
@@ -1,44 +1,99 @@
 package core
 
 import (
+	"context"
 	"github.com/mediocregopher/radix/v4"
 	"go.slink.ws/redisson/api"
+	"iter"
 )
 
 type rset struct {
 	client api.Redis
 	key    string
+	codec  api.Codec
 }
 
 func NewRSet(key string, client api.Redis) api.RSet {
+	return NewRSetWithCodec(key, client, client.Codec())
+}
+func NewRSetWithCodec(key string, client api.Redis, codec api.Codec) api.RSet {
 	return &rset{
 		client: client,
-		key:    key,
+		key:    namespacedKey(client, key),
+		codec:  codecOrDefault(codec),
 	}
 }
 
 func (s *rset) Size() int {
-	var result int
-	_ = s.client.Do(radix.Cmd(&result, "SCARD", s.key))
+	result, _ := s.SizeE()
 	return result
 }
+func (s *rset) SizeE() (int, error) {
+	var result int
+	err := s.client.Do(radix.Cmd(&result, "SCARD", s.key))
+	return result, err
+}
 func (s *rset) Add(values ...any) error {
-	return s.client.Do(radix.Cmd(nil, "SADD", s.client.AnyArgs(s.key, values...)...))
+	args, err := encodeArgs(s.codec, s.key, values...)
+	if err != nil {
+		return err
+	}
+	return s.client.Do(radix.Cmd(nil, "SADD", args...))
 }
 func (s *rset) Has(value any) bool {
+	result, _ := s.HasE(value)
+	return result
+}
+func (s *rset) HasE(value any) (bool, error) {
 	var result int
-	_ = s.client.Do(radix.Cmd(&result, "SISMEMBER", s.client.AnyArgs(s.key, value)...))
-	return result > 0
+	args, err := encodeArgs(s.codec, s.key, value)
+	if err != nil {
+		return false, err
+	}
+	err = s.client.Do(radix.Cmd(&result, "SISMEMBER", args...))
+	return result > 0, err
 }
 func (s *rset) Del(values ...any) error {
-	return s.client.Do(radix.Cmd(nil, "SREM", s.client.AnyArgs(s.key, values...)...))
+	args, err := encodeArgs(s.codec, s.key, values...)
+	if err != nil {
+		return err
+	}
+	return s.client.Do(radix.Cmd(nil, "SREM", args...))
 }
 func (s *rset) Items() []api.Value {
+	values, _ := s.ItemsE()
+	return values
+}
+func (s *rset) ItemsE() ([]api.Value, error) {
 	var result []string
-	_ = s.client.Do(radix.Cmd(&result, "SMEMBERS", s.key))
+	err := s.client.Do(radix.Cmd(&result, "SMEMBERS", s.key))
 	var values []api.Value
 	for _, v := range result {
-		values = append(values, NewValue(v))
+		values = append(values, newCodecValue(v, s.codec))
+	}
+	return values, err
+}
+func (s *rset) Scan(ctx context.Context, match string, count int) iter.Seq2[api.Value, error] {
+	opts := scanOptions(match, count, "")
+	return func(yield func(api.Value, error) bool) {
+		more := true
+		err := scanCursor(s.client.WithContext(ctx), "SSCAN", s.key, opts, func(items []string) bool {
+			for _, item := range items {
+				if more = yield(newCodecValue(item, s.codec), nil); !more {
+					return false
+				}
+			}
+			return true
+		})
+		if more && err != nil {
+			yield(nil, err)
+		}
+	}
+}
+func (s *rset) WithContext(ctx context.Context) api.RSet {
+	return &rset{
+		client: s.client.WithContext(ctx),
+		key:    s.key,
+		codec:  s.codec,
 	}
-	return values
 }
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"go.slink.ws/redisson/api"
 	"testing"
 )
@@ -60,3 +61,30 @@ func TestRSet(t *testing.T) {
 	_, _ = r.Del("TEST_SET")
 
 }
+func TestRSetBinary(t *testing.T) {
+
+	r, err := createClient()
+	if err != nil {
+		t.Error(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	s := NewRSet("TEST_SET", r)
+
+	_ = s.Add(testBinaryData)
+
+	if !s.Has(testBinaryData) {
+		t.Errorf("expected 'true', received 'false'")
+	}
+	items := s.Items()
+	if len(items) != 1 {
+		t.Errorf("expected 1, received %d", len(items))
+	} else if !bytes.Equal(items[0].AsBytes(), testBinaryData) {
+		t.Errorf("expected '%v', received '%v'", testBinaryData, items[0].AsBytes())
+	}
+
+	_, _ = r.Del("TEST_SET")
+
+}
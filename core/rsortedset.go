@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"iter"
+	"strconv"
+)
+
+type rsortedset struct {
+	client api.Redis
+	key    string
+	codec  api.Codec
+}
+
+func NewRSortedSet(key string, client api.Redis) api.RSortedSet {
+	return NewRSortedSetWithCodec(key, client, client.Codec())
+}
+func NewRSortedSetWithCodec(key string, client api.Redis, codec api.Codec) api.RSortedSet {
+	return &rsortedset{
+		client: client,
+		key:    namespacedKey(client, key),
+		codec:  codecOrDefault(codec),
+	}
+}
+
+func (s *rsortedset) Size() int {
+	result, _ := s.SizeE()
+	return result
+}
+func (s *rsortedset) SizeE() (int, error) {
+	var result int
+	err := s.client.Do(radix.Cmd(&result, "ZCARD", s.key))
+	return result, err
+}
+func (s *rsortedset) Add(score float64, value any) error {
+	data, err := s.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	return s.client.Do(radix.Cmd(nil, "ZADD", s.key, strconv.FormatFloat(score, 'f', -1, 64), string(data)))
+}
+func (s *rsortedset) Scan(ctx context.Context, match string, count int) iter.Seq2[api.ScoredEntry, error] {
+	opts := scanOptions(match, count, "")
+	return func(yield func(api.ScoredEntry, error) bool) {
+		more := true
+		var err error
+		scanErr := scanCursor(s.client.WithContext(ctx), "ZSCAN", s.key, opts, func(items []string) bool {
+			// items are member / score pairs
+			for i := 0; i+1 < len(items); i += 2 {
+				var score float64
+				if score, err = strconv.ParseFloat(items[i+1], 64); err != nil {
+					err = fmt.Errorf("unexpected ZSCAN score of member '%s': %w", items[i], err)
+					return false
+				}
+				entry := api.ScoredEntry{Value: newCodecValue(items[i], s.codec), Score: score}
+				if more = yield(entry, nil); !more {
+					return false
+				}
+			}
+			return true
+		})
+		if err == nil {
+			err = scanErr
+		}
+		if more && err != nil {
+			yield(api.ScoredEntry{}, err)
+		}
+	}
+}
+func (s *rsortedset) WithContext(ctx context.Context) api.RSortedSet {
+	return &rsortedset{
+		client: s.client.WithContext(ctx),
+		key:    s.key,
+		codec:  s.codec,
+	}
+}
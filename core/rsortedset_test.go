@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.slink.ws/redisson/api"
+	"math"
+	"testing"
+)
+
+func TestRSortedSet(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	defer func() {
+		_, _ = r.Del("TEST_SORTED_SET", "TEST_SORTED_SET_STRING")
+	}()
+	ctx := context.Background()
+
+	s := NewRSortedSet("TEST_SORTED_SET", r)
+	if s.Size() != 0 {
+		t.Errorf("expected 0, received %d", s.Size())
+	}
+	for i := 0; i < 20; i++ {
+		if err = s.Add(float64(i)/2, fmt.Sprintf("member%02d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = s.Add(math.Inf(-1), "min")
+	if s.Size() != 21 {
+		t.Errorf("expected 21, received %d", s.Size())
+	}
+
+	scores := map[string]float64{}
+	for entry, err := range s.Scan(ctx, "member1*", 3) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		scores[entry.Value.String()] = entry.Score
+	}
+	if len(scores) != 10 || scores["member15"] != 7.5 {
+		t.Errorf("unexpected scores %v", scores)
+	}
+	for entry, err := range s.Scan(ctx, "min", 0) {
+		if err != nil || !math.IsInf(entry.Score, -1) {
+			t.Errorf("expected -inf score, received %v '%v'", entry.Score, err)
+		}
+	}
+
+	// iteration stops when loop is exited
+	n := 0
+	for range s.Scan(ctx, "", 2) {
+		if n++; n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Errorf("expected 5 members, received %d", n)
+	}
+
+	// scan of a key of another type fails
+	_ = r.Set("TEST_SORTED_SET_STRING", "value")
+	for _, err := range NewRSortedSet("TEST_SORTED_SET_STRING", r).Scan(ctx, "", 0) {
+		if !errors.Is(err, ErrWrongType) {
+			t.Errorf("expected '%v', received '%v'", ErrWrongType, err)
+		}
+	}
+}
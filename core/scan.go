@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// scanOptions returns MATCH / COUNT / TYPE arguments of SCAN-family commands
+func scanOptions(match string, count int, typ string) []string {
+	var opts []string
+	if match != "" {
+		opts = append(opts, "MATCH", match)
+	}
+	if count > 0 {
+		opts = append(opts, "COUNT", strconv.Itoa(count))
+	}
+	if typ != "" {
+		opts = append(opts, "TYPE", typ)
+	}
+	return opts
+}
+
+// scanCursor iterates cursor of SCAN-family command (key is empty for SCAN) passing items of every reply to yield;
+// iteration ends when redis returns zero cursor or yield returns false
+func scanCursor(client api.Redis, cmd string, key string, opts []string, yield func(items []string) bool) error {
+	cursor := "0"
+	for {
+		var args []string
+		if key != "" {
+			args = append(args, key)
+		}
+		args = append(append(args, cursor), opts...)
+		// reply is [cursor, [item, ...]]; bulk strings are received as []byte
+		var reply []interface{}
+		if err := client.Do(radix.Cmd(&reply, cmd, args...)); err != nil {
+			return err
+		}
+		if len(reply) != 2 {
+			return fmt.Errorf("unexpected %s reply of %d elements", cmd, len(reply))
+		}
+		cursor = fmt.Sprintf("%s", reply[0])
+		values, _ := reply[1].([]interface{})
+		items := make([]string, len(values))
+		for i, value := range values {
+			items[i] = fmt.Sprintf("%s", value)
+		}
+		if !yield(items) || cursor == "0" {
+			return nil
+		}
+	}
+}
+
+func (r *redis) Scan(ctx context.Context, match string, count int, typ string) iter.Seq2[string, error] {
+	if match == "" {
+		match = "*"
+	}
+	opts := scanOptions(escapeGlob(r.prefix)+match, count, typ)
+	return func(yield func(string, error) bool) {
+		scan := func(client api.Redis) (bool, error) {
+			more := true
+			err := scanCursor(client, "SCAN", "", opts, func(keys []string) bool {
+				for _, key := range r.stripKeys(keys) {
+					if more = yield(key, nil); !more {
+						return false
+					}
+				}
+				return true
+			})
+			return more, err
+		}
+		if r.cluster == nil {
+			if more, err := scan(r.WithContext(ctx)); more && err != nil {
+				yield("", err)
+			}
+			return
+		}
+		clients, err := r.masters()
+		if err != nil {
+			yield("", wrapError(err))
+			return
+		}
+		for _, addr := range slices.Sorted(maps.Keys(clients)) {
+			more, err := scan(r.WithContext(withNode(ctx, clients[addr])))
+			if !more {
+				return
+			}
+			if err != nil && !yield("", fmt.Errorf("%s %s: %w", roleMaster, addr, err)) {
+				return
+			}
+		}
+	}
+}
+
+// hscan iterates hash entries with HSCAN
+func hscan(ctx context.Context, client api.Redis, key string, codec api.Codec, match string, count int) iter.Seq2[api.MapEntry, error] {
+	opts := scanOptions(match, count, "")
+	return func(yield func(api.MapEntry, error) bool) {
+		more := true
+		err := scanCursor(client.WithContext(ctx), "HSCAN", key, opts, func(items []string) bool {
+			// items are field / value pairs
+			for i := 0; i+1 < len(items); i += 2 {
+				entry := api.MapEntry{Key: items[i], Value: newCodecValue(items[i+1], codec)}
+				if more = yield(entry, nil); !more {
+					return false
+				}
+			}
+			return true
+		})
+		if more && err != nil {
+			yield(api.MapEntry{}, err)
+		}
+	}
+}
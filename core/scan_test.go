@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.slink.ws/redisson/api"
+	"slices"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	var expected []string
+	for i := 0; i < 25; i++ {
+		key := fmt.Sprintf("TEST_SCAN_%02d", i)
+		if err = r.Set(key, i); err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, key)
+	}
+	_ = NewRSet("TEST_SCAN_SET", r).Add("a")
+	defer func() {
+		_, _ = r.Del(append(expected, "TEST_SCAN_SET")...)
+	}()
+	ctx := context.Background()
+
+	var keys []string
+	for key, err := range r.Scan(ctx, "TEST_SCAN_*", 4, "string") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(keys, expected) {
+		t.Errorf("expected %v, received %v", expected, keys)
+	}
+
+	// iteration stops when loop is exited
+	n := 0
+	for range r.Scan(ctx, "TEST_SCAN_*", 4, "") {
+		if n++; n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Errorf("expected 5 keys, received %d", n)
+	}
+
+	keys = keys[:0]
+	for key, err := range r.Namespace("TEST_SCAN_").Scan(ctx, "", 0, "set") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	if !slices.Equal(keys, []string{"SET"}) {
+		t.Errorf("expected [SET], received %v", keys)
+	}
+}
+func TestScanCollections(t *testing.T) {
+	r, err := createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	defer func() {
+		_, _ = r.Del("TEST_SCAN_MAP", "TEST_SCAN_SET")
+	}()
+	ctx := context.Background()
+
+	m := NewRMap("TEST_SCAN_MAP", r)
+	s := NewRSet("TEST_SCAN_SET", r)
+	for i := 0; i < 20; i++ {
+		_ = m.Set(fmt.Sprintf("field%02d", i), i)
+		_ = s.Add(i)
+	}
+
+	entries := map[string]int{}
+	for entry, err := range m.Scan(ctx, "field1*", 3) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[entry.Key] = entry.Value.AsInt()
+	}
+	if len(entries) != 10 || entries["field15"] != 15 {
+		t.Errorf("unexpected entries %v", entries)
+	}
+
+	sum := 0
+	for value, err := range s.Scan(ctx, "", 3) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum += value.AsInt()
+	}
+	if sum != 190 {
+		t.Errorf("expected 190, received %d", sum)
+	}
+
+	// scan of a key of another type fails
+	for _, err := range NewRSet("TEST_SCAN_MAP", r).Scan(ctx, "", 0) {
+		if !errors.Is(err, ErrWrongType) {
+			t.Errorf("expected '%v', received '%v'", ErrWrongType, err)
+		}
+	}
+}
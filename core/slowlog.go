@@ -0,0 +1,59 @@
+package core
+
+import (
+	"go.slink.ws/redisson/api"
+	"sync"
+	"time"
+)
+
+const defaultSlowLogSize = 128
+
+// slowLog keeps the last slow commands in a ring buffer
+type slowLog struct {
+	threshold time.Duration
+	mutex     sync.Mutex
+	entries   []api.SlowCommand
+	next      int
+	full      bool
+}
+
+func newSlowLog(threshold time.Duration, size int) *slowLog {
+	if size <= 0 {
+		size = defaultSlowLogSize
+	}
+	return &slowLog{
+		threshold: threshold,
+		entries:   make([]api.SlowCommand, size),
+	}
+}
+
+// observe records command if it exceeds threshold and reports whether it did
+func (l *slowLog) observe(command api.Command, start time.Time, err error) (api.SlowCommand, bool) {
+	elapsed := time.Since(start)
+	if elapsed < l.threshold {
+		return api.SlowCommand{}, false
+	}
+	entry := api.SlowCommand{
+		Name:     command.Name,
+		KeyCount: len(command.Keys),
+		Start:    start,
+		Elapsed:  elapsed,
+		Err:      err,
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	l.full = l.full || l.next == 0
+	return entry, true
+}
+
+// commands returns recorded commands, the oldest first
+func (l *slowLog) commands() []api.SlowCommand {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.full {
+		return append([]api.SlowCommand{}, l.entries[:l.next]...)
+	}
+	return append(append([]api.SlowCommand{}, l.entries[l.next:]...), l.entries[:l.next]...)
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"testing"
+	"time"
+)
+
+func TestSlowLog(t *testing.T) {
+	l := newSlowLog(time.Millisecond, 2)
+	start := time.Now().Add(-time.Second)
+	l.observe(api.Command{Name: "GET"}, time.Now(), nil)
+	if commands := l.commands(); len(commands) != 0 {
+		t.Errorf("expected no slow commands, got %v", commands)
+	}
+	for _, name := range []string{"SET", "DEL", "MGET"} {
+		l.observe(api.Command{Name: name, Keys: []string{"a", "b"}}, start, nil)
+	}
+	commands := l.commands()
+	if len(commands) != 2 || commands[0].Name != "DEL" || commands[1].Name != "MGET" {
+		t.Fatalf("expected the last two commands, got %v", commands)
+	}
+	if commands[0].KeyCount != 2 || commands[0].Elapsed < time.Second {
+		t.Errorf("unexpected command %+v", commands[0])
+	}
+}
+func TestSlowCommands(t *testing.T) {
+	logger := &testStructuredLogger{}
+	r, err := NewConfig().
+		WithLogger(logger).
+		WithSlowCommandThreshold(50 * time.Millisecond).
+		WithSlowCommandLogSize(2).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	_, _ = r.Get("TEST_SLOW_KEY")
+	if commands := r.SlowCommands(); len(commands) != 0 {
+		t.Errorf("expected no slow commands, got %v", commands)
+	}
+	for i := 0; i < 3; i++ {
+		err = r.Do(radix.Cmd(nil, "DEBUG", "SLEEP", "0.1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	commands := r.SlowCommands()
+	if len(commands) != 2 || commands[0].Name != "DEBUG" || commands[1].Elapsed < 100*time.Millisecond {
+		t.Errorf("unexpected slow commands %v", commands)
+	}
+	entry, ok := logger.find("slow command")
+	if !ok || entry.level != api.LevelWarning || entry.fields["command"] != "DEBUG" || entry.fields["keys"] != 0 {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	r, err = createClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+	if commands := r.SlowCommands(); commands != nil {
+		t.Errorf("expected slow log to be disabled")
+	}
+}
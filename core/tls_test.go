@@ -0,0 +1,206 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"github.com/stvp/tempredis"
+	"go.slink.ws/redisson/api"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testTLSServerPort = 51201
+
+type testCertificates struct {
+	caFile, certFile, keyFile, clientCertFile, clientKeyFile string
+}
+
+func TestTLSConfig(t *testing.T) {
+	certs := generateTestCertificates(t)
+
+	cfg := NewConfig().
+		WithTLSCACertFile(certs.caFile).
+		WithTLSClientCertFile(certs.clientCertFile, certs.clientKeyFile).
+		WithTLSServerName("localhost")
+	if cfg.err != nil {
+		t.Error(cfg.err)
+	}
+	if cfg.tls == nil {
+		t.Fatalf("expected non-null value")
+	}
+	if cfg.tls.RootCAs == nil {
+		t.Errorf("expected non-null value")
+	}
+	if len(cfg.tls.Certificates) != 1 {
+		t.Errorf("expected 1, received %d", len(cfg.tls.Certificates))
+	}
+	if cfg.tls.ServerName != "localhost" {
+		t.Errorf("expected '%s', received '%s'", "localhost", cfg.tls.ServerName)
+	}
+
+	cfg = NewConfig().WithTLSCACertFile(filepath.Join(t.TempDir(), "missing.pem"))
+	if cfg.err == nil {
+		t.Errorf("expected error, received nil")
+	}
+	_, err := cfg.NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+
+	cfg = NewConfig().WithTLSCACert([]byte("not a certificate"))
+	if cfg.err == nil {
+		t.Errorf("expected error, received nil")
+	}
+
+	// caller's config is not modified
+	orig := &tls.Config{MinVersion: tls.VersionTLS13}
+	cfg = NewConfig().WithTLS(orig).WithTLSServerName("localhost").WithTLSClientCertFile(certs.clientCertFile, certs.clientKeyFile)
+	if orig.ServerName != "" || len(orig.Certificates) != 0 {
+		t.Errorf("expected unmodified config, received '%s' %d", orig.ServerName, len(orig.Certificates))
+	}
+	if cfg.tls == orig || cfg.tls.ServerName != "localhost" || len(cfg.tls.Certificates) != 1 {
+		t.Errorf("expected copied config")
+	}
+}
+func TestTLSClient(t *testing.T) {
+	certs := generateTestCertificates(t)
+
+	tlsServer, err := tempredis.Start(tempredis.Config{
+		"port":             "0",
+		"tls-port":         strconv.Itoa(testTLSServerPort),
+		"tls-cert-file":    certs.certFile,
+		"tls-key-file":     certs.keyFile,
+		"tls-ca-cert-file": certs.caFile,
+		"tls-auth-clients": "yes",
+	})
+	if err != nil {
+		t.Skipf("could not start TLS-enabled redis server: %s", err)
+	}
+	defer func() {
+		_ = tlsServer.Term()
+	}()
+
+	addr := fmt.Sprintf("%s:%d", testServerHost, testTLSServerPort)
+
+	r, err := NewConfig().
+		WithName("TEST-TLS-CLIENT").
+		WithTLSCACertFile(certs.caFile).
+		WithTLSClientCertFile(certs.clientCertFile, certs.clientKeyFile).
+		WithTLSServerName("localhost").
+		NewSingle(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	err = r.Set("TEST_KEY", "TEST_VALUE")
+	if err != nil {
+		t.Error(err)
+	}
+	v, err := r.Get("TEST_KEY")
+	if err != nil {
+		t.Error(err)
+	}
+	if v.String() != "TEST_VALUE" {
+		t.Errorf("expected '%s', but received '%s'", "TEST_VALUE", v.String())
+	}
+	_, _ = r.Del("TEST_KEY")
+
+	psconn, err := r.PubSub()
+	if err != nil {
+		t.Error(err)
+	} else {
+		_ = psconn.Close()
+	}
+
+	// server requires client certificate; with TLS 1.3 rejection is reported on first command
+	r2, err := NewConfig().
+		WithTLSCACertFile(certs.caFile).
+		WithTLSServerName("localhost").
+		NewSingle(addr)
+	if err == nil {
+		err = r2.Set("TEST_KEY", "TEST_VALUE")
+		_ = r2.Close()
+	}
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+}
+
+func generateTestCertificates(t *testing.T) testCertificates {
+	dir := t.TempDir()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redisson test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs := testCertificates{
+		caFile: writeTestPem(t, dir, "ca.pem", "CERTIFICATE", caDer),
+	}
+	certs.certFile, certs.keyFile = generateTestCertificate(t, dir, "server", caCert, caKey, x509.ExtKeyUsageServerAuth)
+	certs.clientCertFile, certs.clientKeyFile = generateTestCertificate(t, dir, "client", caCert, caKey, x509.ExtKeyUsageClientAuth)
+	return certs
+}
+func generateTestCertificate(t *testing.T, dir, name string, ca *x509.Certificate, caKey *ecdsa.PrivateKey, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP(testServerHost)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeTestPem(t, dir, name+".pem", "CERTIFICATE", der),
+		writeTestPem(t, dir, name+".key", "EC PRIVATE KEY", keyDer)
+}
+func writeTestPem(t *testing.T, dir, name, blockType string, data []byte) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"context"
+	"go.slink.ws/redisson/api"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// span attributes, see https://opentelemetry.io/docs/specs/semconv/database/redis/
+const (
+	spanAttrDbSystem      = "db.system"
+	spanAttrDbOperation   = "db.operation"
+	spanAttrDbStatement   = "db.statement"
+	spanAttrDbBatchSize   = "db.operation.batch.size"
+	spanAttrRedisKey      = "db.redis.key"
+	spanAttrRedisKeys     = "db.redis.keys"
+	spanAttrServerAddress = "server.address"
+	spanAttrServerPort    = "server.port"
+)
+
+const spanDbSystem = "redis"
+const spanDefaultName = "redis"
+const spanCacheMapSyncName = "RCacheMap.sync"
+
+// spanContextKey carries function adding attributes to the spans of command being issued
+type spanContextKey struct{}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...api.SpanAttribute) {}
+func (noopSpan) End(error)                          {}
+
+// startSpan starts span with client tracer; spans of commands issued with returned context are its children
+func (r *redis) startSpan(ctx context.Context, name string, attrs ...api.SpanAttribute) (context.Context, api.Span) {
+	if r.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	attrs = append([]api.SpanAttribute{{Key: spanAttrDbSystem, Value: spanDbSystem}}, attrs...)
+	return r.tracer.Start(ctx, name, attrs...)
+}
+
+// traceCommand starts command span; pipelines and transactions get child span for every command
+func (r *redis) traceCommand(ctx context.Context, command api.Command, args []string) (context.Context, func(error)) {
+	multi, ok := command.Action.(*multiAction)
+	if !ok {
+		ctx, span := r.startSpan(ctx, spanName(command), commandSpanAttributes(command, args)...)
+		return context.WithValue(ctx, spanContextKey{}, span.SetAttributes), span.End
+	}
+	ctx, span := r.startSpan(ctx, spanName(command),
+		api.SpanAttribute{Key: spanAttrDbOperation, Value: command.Name},
+		api.SpanAttribute{Key: spanAttrDbBatchSize, Value: len(multi.cmds)},
+	)
+	spans := []api.Span{span}
+	for _, cmd := range multi.cmds {
+		command, args := newCommand(cmd)
+		_, s := r.startSpan(ctx, spanName(command), commandSpanAttributes(command, args)...)
+		spans = append(spans, s)
+	}
+	ctx = context.WithValue(ctx, spanContextKey{}, func(attrs ...api.SpanAttribute) {
+		for _, s := range spans {
+			s.SetAttributes(attrs...)
+		}
+	})
+	return ctx, func(err error) {
+		// children are ended first
+		for _, s := range slices.Backward(spans) {
+			s.End(err)
+		}
+	}
+}
+
+// traceServer adds server address to the spans of command issued with ctx
+func traceServer(ctx context.Context, addr string) {
+	setAttrs, ok := ctx.Value(spanContextKey{}).(func(...api.SpanAttribute))
+	if !ok {
+		return
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		// unix socket path
+		setAttrs(api.SpanAttribute{Key: spanAttrServerAddress, Value: addr})
+		return
+	}
+	p, _ := strconv.Atoi(port)
+	setAttrs(
+		api.SpanAttribute{Key: spanAttrServerAddress, Value: host},
+		api.SpanAttribute{Key: spanAttrServerPort, Value: p},
+	)
+}
+
+func spanName(command api.Command) string {
+	if command.Name == "" {
+		return spanDefaultName
+	}
+	return command.Name
+}
+
+// commandSpanAttributes describes command; arguments other than keys are replaced with '?' in statement
+func commandSpanAttributes(command api.Command, args []string) []api.SpanAttribute {
+	attrs := []api.SpanAttribute{{Key: spanAttrDbOperation, Value: command.Name}}
+	if command.Name != "" {
+		statement := []string{command.Name}
+		keys := keyIndexes(command.Name, args)
+		for i, arg := range args {
+			if slices.Contains(keys, i) {
+				statement = append(statement, arg)
+			} else {
+				statement = append(statement, "?")
+			}
+		}
+		attrs = append(attrs, api.SpanAttribute{Key: spanAttrDbStatement, Value: strings.Join(statement, " ")})
+	}
+	switch len(command.Keys) {
+	case 0:
+	case 1:
+		attrs = append(attrs, api.SpanAttribute{Key: spanAttrRedisKey, Value: command.Keys[0]})
+	default:
+		attrs = append(attrs, api.SpanAttribute{Key: spanAttrRedisKeys, Value: command.Keys})
+	}
+	return attrs
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/mediocregopher/radix/v4"
+	"go.slink.ws/redisson/api"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type testSpan struct {
+	tracer *testTracer
+	name   string
+	parent *testSpan
+	attrs  map[string]any
+	ended  bool
+	err    error
+}
+
+func (s *testSpan) SetAttributes(attrs ...api.SpanAttribute) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+func (s *testSpan) End(err error) {
+	s.tracer.mutex.Lock()
+	defer s.tracer.mutex.Unlock()
+	s.ended = true
+	s.err = err
+}
+
+type testSpanKey struct{}
+
+// testTracer records started spans
+type testTracer struct {
+	mutex sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs ...api.SpanAttribute) (context.Context, api.Span) {
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{tracer: t, name: name, parent: parent, attrs: map[string]any{}}
+	span.SetAttributes(attrs...)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+func (t *testTracer) find(name string) []*testSpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var spans []*testSpan
+	for _, span := range t.spans {
+		if span.name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &testTracer{}
+	r, err := NewConfig().
+		WithTracer(tracer).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	err = r.Set("TEST_TRACE_KEY", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spans := tracer.find("SET")
+	if len(spans) != 1 {
+		t.Fatalf("expected single SET span, got %d", len(spans))
+	}
+	span := spans[0]
+	if !span.ended || span.err != nil || span.parent != nil {
+		t.Errorf("unexpected span state: ended=%v err=%v parent=%v", span.ended, span.err, span.parent)
+	}
+	for key, value := range map[string]any{
+		spanAttrDbSystem:      "redis",
+		spanAttrDbOperation:   "SET",
+		spanAttrDbStatement:   "SET TEST_TRACE_KEY ?",
+		spanAttrRedisKey:      "TEST_TRACE_KEY",
+		spanAttrServerAddress: testServerHost,
+		spanAttrServerPort:    testServerPort,
+	} {
+		if span.attrs[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, span.attrs[key])
+		}
+	}
+
+	// arguments are kept by position, value equal to key name is hidden as well
+	_ = r.Do(radix.Cmd(nil, "SET", "TEST_TRACE_KEY", "TEST_TRACE_KEY"))
+	if spans := tracer.find("SET"); len(spans) != 2 || spans[1].attrs[spanAttrDbStatement] != "SET TEST_TRACE_KEY ?" {
+		t.Errorf("expected statement with hidden value")
+	}
+
+	err = r.Do(radix.Cmd(nil, "DEL", "TEST_TRACE_KEY", "TEST_TRACE_KEY2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spans := tracer.find("DEL"); len(spans) != 1 ||
+		!slices.Equal(spans[0].attrs[spanAttrRedisKeys].([]string), []string{"TEST_TRACE_KEY", "TEST_TRACE_KEY2"}) {
+		t.Errorf("expected DEL span with both keys")
+	}
+
+	err = r.Do(radix.Cmd(nil, "NO_SUCH_COMMAND"))
+	if spans := tracer.find("NO_SUCH_COMMAND"); len(spans) != 1 || spans[0].err == nil {
+		t.Errorf("expected failed span")
+	}
+}
+func TestTracerPipeline(t *testing.T) {
+	tracer := &testTracer{}
+	r, err := NewConfig().
+		WithTracer(tracer).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	err = r.Pipeline(
+		radix.Cmd(nil, "SET", "TEST_TRACE_PIPELINE", "1"),
+		radix.Cmd(nil, "INCR", "TEST_TRACE_PIPELINE"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parents := tracer.find("PIPELINE")
+	if len(parents) != 1 {
+		t.Fatalf("expected single PIPELINE span, got %d", len(parents))
+	}
+	if parents[0].attrs[spanAttrDbBatchSize] != 2 || !parents[0].ended {
+		t.Errorf("unexpected pipeline span: %v", parents[0].attrs)
+	}
+	for _, name := range []string{"SET", "INCR"} {
+		spans := tracer.find(name)
+		if len(spans) != 1 || spans[0].parent != parents[0] || !spans[0].ended {
+			t.Fatalf("expected %s span within pipeline", name)
+		}
+		if spans[0].attrs[spanAttrServerPort] != testServerPort {
+			t.Errorf("expected server port on %s span", name)
+		}
+	}
+
+	err = r.Transaction(radix.Cmd(nil, "INCR", "TEST_TRACE_PIPELINE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parents := tracer.find("MULTI"); len(parents) != 1 || len(tracer.find("INCR")) != 2 {
+		t.Errorf("expected transaction span")
+	}
+}
+func TestTracerCacheMap(t *testing.T) {
+	tracer := &testTracer{}
+	r, err := NewConfig().
+		WithTracer(tracer).
+		NewSingle(fmt.Sprintf("%s:%d", testServerHost, testServerPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(r api.Redis) {
+		_ = r.Close()
+	}(r)
+
+	err = NewRMap("TEST_TRACE_CACHE_MAP", r).Set("key", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewRCacheMap("TEST_TRACE_CACHE_MAP", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Destroy()
+	_ = m.Keys()
+
+	syncs := tracer.find(spanCacheMapSyncName)
+	if len(syncs) == 0 || syncs[0].attrs[spanAttrRedisKey] != "TEST_TRACE_CACHE_MAP" {
+		t.Fatalf("expected cache map sync span")
+	}
+	var children int
+	for _, name := range []string{"HKEYS", "HGET"} {
+		for _, span := range tracer.find(name) {
+			if span.parent == syncs[0] {
+				children++
+			}
+		}
+	}
+	if children != 2 {
+		t.Errorf("expected 2 commands within sync span, got %d", children)
+	}
+}
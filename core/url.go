@@ -0,0 +1,234 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"go.slink.ws/redisson/api"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultRedisPort = "6379"
+const defaultSentinelPort = "26379"
+
+// ParseURL creates configuration from connection string; supported formats are:
+//
+//	redis://[[user]:password@]host[:port][/db][?params]                  - single node
+//	redis://[[user]:password@]host1[:port],host2[:port][?params]         - cluster
+//	redis+cluster://[[user]:password@]host1[:port],host2[:port][?params] - cluster
+//	redis+sentinel://[[user]:password@]host1[:port],host2[:port]/master[/db][?params] - sentinel
+//	unix://[[user]:password@]/path/to/redis.sock[?params]                - unix domain socket
+//
+// "rediss" scheme variants (rediss://, rediss+cluster://, rediss+sentinel://) enable TLS.
+// Supported parameters are: db, client_name, pool_size, ping_interval, dial_timeout, read_timeout, write_timeout.
+// Client is created from parsed configuration with config.New().
+func ParseURL(rawURL string) (*config, error) {
+	// net/url does not accept multiple hosts, so they are cut out before parsing
+	rawURL, hosts := splitURLHosts(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+	c := NewConfig()
+
+	scheme, topology, _ := strings.Cut(u.Scheme, "+")
+	switch scheme {
+	case "redis":
+	case "rediss":
+		c.tlsConfig()
+	case "unix":
+		if topology != "" {
+			return nil, fmt.Errorf("%w: unsupported scheme '%s'", ErrInvalidURL, u.Scheme)
+		}
+		c.WithNetwork("unix")
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme '%s'", ErrInvalidURL, u.Scheme)
+	}
+
+	if u.User != nil {
+		password, _ := u.User.Password()
+		c.WithAuth(u.User.Username(), password)
+	}
+
+	var path []string
+	if c.network == "unix" {
+		if u.Path == "" {
+			return nil, fmt.Errorf("%w: socket path is not set", ErrInvalidURL)
+		}
+		c.addrs = []string{u.Path}
+	} else {
+		path = strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+		if u.Host == "" {
+			return nil, fmt.Errorf("%w: host is not set", ErrInvalidURL)
+		}
+		defaultPort := defaultRedisPort
+		if topology == "sentinel" {
+			defaultPort = defaultSentinelPort
+		}
+		if hosts == nil {
+			hosts = []string{u.Host}
+		}
+		for _, host := range hosts {
+			addr, err := urlHostAddr(host, defaultPort)
+			if err != nil {
+				return nil, err
+			}
+			c.addrs = append(c.addrs, addr)
+		}
+	}
+
+	switch topology {
+	case "":
+		c.cluster = len(c.addrs) > 1
+	case "cluster":
+		c.cluster = true
+	case "sentinel":
+		if len(path) == 0 {
+			return nil, fmt.Errorf("%w: sentinel master name is not set", ErrInvalidURL)
+		}
+		c.masterName, path = path[0], path[1:]
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme '%s'", ErrInvalidURL, u.Scheme)
+	}
+
+	if len(path) > 1 {
+		return nil, fmt.Errorf("%w: unexpected path '%s'", ErrInvalidURL, u.Path)
+	}
+	if len(path) == 1 {
+		db, err := urlIntParam("db", path[0])
+		if err != nil {
+			return nil, err
+		}
+		c.WithDb(db)
+	}
+
+	err = c.parseURLQuery(u.Query())
+	if err != nil {
+		return nil, err
+	}
+	if c.cluster && c.db != 0 {
+		return nil, fmt.Errorf("%w: cluster supports database 0 only", ErrInvalidURL)
+	}
+	return c, nil
+}
+
+// New creates redis client from configuration parsed with ParseURL
+func (c *config) New() (api.Redis, error) {
+	switch {
+	case c.masterName != "":
+		return c.NewSentinel(c.masterName, c.addrs...)
+	case c.cluster:
+		return c.NewCluster(c.addrs...)
+	case len(c.addrs) == 1:
+		return c.NewSingle(c.addrs[0])
+	default:
+		return nil, errors.New("redis address is not configured")
+	}
+}
+
+func (c *config) parseURLQuery(query url.Values) error {
+	for param, values := range query {
+		value := values[len(values)-1]
+		switch param {
+		case "db":
+			db, err := urlIntParam(param, value)
+			if err != nil {
+				return err
+			}
+			c.WithDb(db)
+		case "client_name":
+			c.WithName(value)
+		case "pool_size":
+			sz, err := urlIntParam(param, value)
+			if err != nil {
+				return err
+			}
+			if sz == 0 {
+				return fmt.Errorf("%w: %s should be positive", ErrInvalidURL, param)
+			}
+			c.WithPoolSize(sz)
+		case "ping_interval":
+			d, err := urlDurationParam(param, value)
+			if err != nil {
+				return err
+			}
+			c.WithPingInterval(d)
+		case "dial_timeout":
+			d, err := urlDurationParam(param, value)
+			if err != nil {
+				return err
+			}
+			c.WithDialTimeout(d)
+		case "read_timeout":
+			d, err := urlDurationParam(param, value)
+			if err != nil {
+				return err
+			}
+			c.WithReadTimeout(d)
+		case "write_timeout":
+			d, err := urlDurationParam(param, value)
+			if err != nil {
+				return err
+			}
+			c.WithWriteTimeout(d)
+		default:
+			return fmt.Errorf("%w: unsupported parameter '%s'", ErrInvalidURL, param)
+		}
+	}
+	return nil
+}
+
+// splitURLHosts replaces comma-separated host list with the first host and returns the whole list
+func splitURLHosts(rawURL string) (string, []string) {
+	_, rest, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return rawURL, nil
+	}
+	start := len(rawURL) - len(rest)
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	authority := rest[:end]
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		start += at + 1
+		authority = authority[at+1:]
+	}
+	if !strings.Contains(authority, ",") {
+		return rawURL, nil
+	}
+	hosts := strings.Split(authority, ",")
+	return rawURL[:start] + hosts[0] + rawURL[start+len(authority):], hosts
+}
+func urlHostAddr(host, defaultPort string) (string, error) {
+	if host == "" {
+		return "", fmt.Errorf("%w: empty host", ErrInvalidURL)
+	}
+	h, port, err := net.SplitHostPort(host)
+	if err != nil {
+		// no port specified
+		return net.JoinHostPort(strings.Trim(host, "[]"), defaultPort), nil
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil || p <= 0 || p > 65535 || h == "" {
+		return "", fmt.Errorf("%w: invalid address '%s'", ErrInvalidURL, host)
+	}
+	return host, nil
+}
+func urlIntParam(param, value string) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("%w: invalid %s value '%s'", ErrInvalidURL, param, value)
+	}
+	return i, nil
+}
+func urlDurationParam(param, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%w: invalid %s value '%s'", ErrInvalidURL, param, value)
+	}
+	return d, nil
+}
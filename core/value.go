@@ -2,10 +2,13 @@ package core
 
 import (
 	"fmt"
+	"github.com/mediocregopher/radix/v4"
 	"go.slink.ws/redisson/api"
 	"math"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func NewValue(value any) api.Value {
@@ -14,10 +17,39 @@ func NewValue(value any) api.Value {
 	}
 }
 
+// newCodecValue creates value received from redis which could be decoded with codec
+func newCodecValue(value string, codec api.Codec) api.Value {
+	return &redisValue{
+		value: value,
+		codec: codec,
+	}
+}
+
+// newNilValue creates value representing RESP nil reply (i.e. missing key or field)
+func newNilValue(codec api.Codec) api.Value {
+	return &redisValue{
+		codec: codec,
+		isNil: true,
+	}
+}
+
+// newMaybeValue creates value from optional redis reply
+func newMaybeValue(mb *radix.Maybe, codec api.Codec) api.Value {
+	if mb.Null {
+		return newNilValue(codec)
+	}
+	return newCodecValue(*mb.Rcv.(*string), codec)
+}
+
 type redisValue struct {
 	value any
+	codec api.Codec
+	isNil bool
 }
 
+func (v *redisValue) IsNil() bool {
+	return v.isNil
+}
 func (v *redisValue) IsEmpty() bool {
 	return strings.TrimSpace(v.String()) == ""
 }
@@ -25,11 +57,29 @@ func (v *redisValue) V() any {
 	return v.value
 }
 func (v *redisValue) String() string {
-	return fmt.Sprintf("%v", v.value)
+	if v.isNil {
+		return ""
+	}
+	switch value := v.value.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	default:
+		return fmt.Sprintf("%v", v.value)
+	}
 }
 func (v *redisValue) AsString() string {
 	return v.String()
 }
+func (v *redisValue) AsBytes() []byte {
+	switch value := v.value.(type) {
+	case []byte:
+		return append([]byte{}, value...)
+	default:
+		return []byte(v.String())
+	}
+}
 func (v *redisValue) AsInt() int {
 	i, err := strconv.ParseInt(v.String(), 10, 64)
 	if err != nil {
@@ -51,3 +101,62 @@ func (v *redisValue) AsBool() bool {
 	}
 	return b
 }
+func (v *redisValue) Int64() (int64, error) {
+	if v.isNil {
+		return 0, ErrNotFound
+	}
+	return strconv.ParseInt(v.String(), 10, 64)
+}
+func (v *redisValue) Uint64() (uint64, error) {
+	if v.isNil {
+		return 0, ErrNotFound
+	}
+	return strconv.ParseUint(v.String(), 10, 64)
+}
+func (v *redisValue) Float64() (float64, error) {
+	if v.isNil {
+		return 0, ErrNotFound
+	}
+	return strconv.ParseFloat(v.String(), 64)
+}
+func (v *redisValue) Bool() (bool, error) {
+	if v.isNil {
+		return false, ErrNotFound
+	}
+	return strconv.ParseBool(v.String())
+}
+func (v *redisValue) Duration() (time.Duration, error) {
+	if v.isNil {
+		return 0, ErrNotFound
+	}
+	return parseDuration(v.String())
+}
+func (v *redisValue) Time(layout string) (time.Time, error) {
+	if v.isNil {
+		return time.Time{}, ErrNotFound
+	}
+	return time.Parse(layout, v.String())
+}
+
+// Scan is an alias of Decode
+func (v *redisValue) Scan(dest any) error {
+	return v.Decode(dest)
+}
+func (v *redisValue) Decode(dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode destination should be a non-nil pointer, received %T", dest)
+	}
+	if v.isNil {
+		return ErrNotFound
+	}
+	return codecOrDefault(v.codec).Decode([]byte(v.String()), dest)
+}
+
+// parseDuration parses duration string (i.e. "1m30s") or integer amount of nanoseconds
+func parseDuration(s string) (time.Duration, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(i), nil
+	}
+	return time.ParseDuration(s)
+}
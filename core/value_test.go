@@ -1,8 +1,11 @@
 package core
 
 import (
+	"bytes"
+	"errors"
 	"math"
 	"testing"
+	"time"
 )
 
 const strValue = "test string"
@@ -69,3 +72,109 @@ func TestBoolValue(t *testing.T) {
 		t.Errorf("expected '%v', received '%v'", false, value.AsBool())
 	}
 }
+func TestBytesValue(t *testing.T) {
+	data := []byte{0x00, 0xff, 0xfe, 'a', 0x00}
+	value := NewValue(data)
+	if value.AsString() != string(data) {
+		t.Errorf("expected '%v', received '%v'", data, []byte(value.AsString()))
+	}
+	if !bytes.Equal(value.AsBytes(), data) {
+		t.Errorf("expected '%v', received '%v'", data, value.AsBytes())
+	}
+
+	value = NewValue(string(data))
+	if !bytes.Equal(value.AsBytes(), data) {
+		t.Errorf("expected '%v', received '%v'", data, value.AsBytes())
+	}
+}
+func TestNilValue(t *testing.T) {
+	value := newNilValue(nil)
+	if !value.IsNil() {
+		t.Errorf("expected nil value")
+	}
+	if !value.IsEmpty() {
+		t.Errorf("expected empty value")
+	}
+	var s string
+	if err := value.Decode(&s); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected '%v', received '%v'", ErrNotFound, err)
+	}
+
+	value = NewValue("")
+	if value.IsNil() {
+		t.Errorf("expected non-nil value")
+	}
+}
+func TestTypedAccessors(t *testing.T) {
+	i, err := NewValue(intValue).Int64()
+	if err != nil || i != intValue {
+		t.Errorf("expected '%d', received '%d' (%v)", intValue, i, err)
+	}
+	_, err = NewValue(strValue).Int64()
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+	u, err := NewValue(intValue).Uint64()
+	if err != nil || u != intValue {
+		t.Errorf("expected '%d', received '%d' (%v)", intValue, u, err)
+	}
+	_, err = NewValue(-1).Uint64()
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+	f, err := NewValue(floatValue).Float64()
+	if err != nil || f != floatValue {
+		t.Errorf("expected '%f', received '%f' (%v)", floatValue, f, err)
+	}
+	b, err := NewValue(boolValue).Bool()
+	if err != nil || b != boolValue {
+		t.Errorf("expected '%v', received '%v' (%v)", boolValue, b, err)
+	}
+	_, err = NewValue("").Bool()
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+	d, err := NewValue(90 * time.Second).Duration()
+	if err != nil || d != 90*time.Second {
+		t.Errorf("expected '%v', received '%v' (%v)", 90*time.Second, d, err)
+	}
+	d, err = NewValue(int64(time.Second)).Duration()
+	if err != nil || d != time.Second {
+		t.Errorf("expected '%v', received '%v' (%v)", time.Second, d, err)
+	}
+	ts, err := NewValue("2023-01-02").Time(time.DateOnly)
+	if err != nil || !ts.Equal(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected '%s', received '%v' (%v)", "2023-01-02", ts, err)
+	}
+
+	_, err = newNilValue(nil).Int64()
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected '%v', received '%v'", ErrNotFound, err)
+	}
+}
+func TestScanValue(t *testing.T) {
+	var i int
+	err := NewValue(intValue).Scan(&i)
+	if err != nil || i != intValue {
+		t.Errorf("expected '%d', received '%d' (%v)", intValue, i, err)
+	}
+	var d time.Duration
+	err = NewValue(time.Minute).Scan(&d)
+	if err != nil || d != time.Minute {
+		t.Errorf("expected '%v', received '%v' (%v)", time.Minute, d, err)
+	}
+	err = NewValue(intValue).Scan(i)
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+	err = NewValue(intValue).Decode(i)
+	if err == nil {
+		t.Errorf("expected error, received nil")
+	}
+
+	var s testCodecStruct
+	err = newCodecValue(`{"Name":"test","Count":3}`, NewJsonCodec()).Scan(&s)
+	if err != nil || s.Name != "test" || s.Count != 3 {
+		t.Errorf("expected '%s', received '%v' (%v)", "test", s, err)
+	}
+}
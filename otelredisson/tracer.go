@@ -0,0 +1,68 @@
+// Package otelredisson provides OpenTelemetry implementation of redisson tracer:
+//
+//	client, err := redisson.NewConfig().
+//		WithTracer(otelredisson.NewTracer(otel.GetTracerProvider())).
+//		NewSingle("127.0.0.1:6379")
+package otelredisson
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.slink.ws/redisson/api"
+)
+
+const instrumentationName = "go.slink.ws/redisson/otelredisson"
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates tracer starting client spans with given provider
+func NewTracer(provider trace.TracerProvider) api.Tracer {
+	return &tracer{
+		tracer: provider.Tracer(instrumentationName),
+	}
+}
+
+func (t *tracer) Start(ctx context.Context, name string, attrs ...api.SpanAttribute) (context.Context, api.Span) {
+	ctx, s := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes(attrs)...),
+	)
+	return ctx, &span{span: s}
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s *span) SetAttributes(attrs ...api.SpanAttribute) {
+	s.span.SetAttributes(attributes(attrs)...)
+}
+func (s *span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+func attributes(attrs []api.SpanAttribute) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			result = append(result, attribute.String(attr.Key, v))
+		case int:
+			result = append(result, attribute.Int(attr.Key, v))
+		case []string:
+			result = append(result, attribute.StringSlice(attr.Key, v))
+		default:
+			result = append(result, attribute.String(attr.Key, fmt.Sprint(v)))
+		}
+	}
+	return result
+}
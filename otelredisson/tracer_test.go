@@ -0,0 +1,59 @@
+package otelredisson
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.slink.ws/redisson/api"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewTracer(provider)
+
+	ctx, parent := tracer.Start(context.Background(), "PIPELINE",
+		api.SpanAttribute{Key: "db.system", Value: "redis"})
+	_, child := tracer.Start(ctx, "GET",
+		api.SpanAttribute{Key: "db.redis.key", Value: "key"},
+		api.SpanAttribute{Key: "db.redis.keys", Value: []string{"key"}})
+	child.SetAttributes(api.SpanAttribute{Key: "server.port", Value: 6379})
+	child.End(errors.New("failure"))
+	parent.End(nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	get, pipeline := spans[0], spans[1]
+	if get.Parent().SpanID() != pipeline.SpanContext().SpanID() {
+		t.Errorf("expected GET span to be child of PIPELINE span")
+	}
+	if get.SpanKind() != trace.SpanKindClient {
+		t.Errorf("unexpected span kind %s", get.SpanKind())
+	}
+	if get.Status().Code != codes.Error || pipeline.Status().Code != codes.Unset {
+		t.Errorf("unexpected span statuses %v %v", get.Status(), pipeline.Status())
+	}
+	expected := []attribute.KeyValue{
+		attribute.String("db.redis.key", "key"),
+		attribute.StringSlice("db.redis.keys", []string{"key"}),
+		attribute.Int("server.port", 6379),
+	}
+	for _, kv := range expected {
+		found := false
+		for _, attr := range get.Attributes() {
+			if attr.Key == kv.Key && attr.Value.Emit() == kv.Value.Emit() {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected attribute %s=%s", kv.Key, kv.Value.Emit())
+		}
+	}
+}